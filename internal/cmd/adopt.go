@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var adoptSafeCmd *SafeCommand
+
+var adoptCmd = &cobra.Command{
+	Use:   "adopt <file>...",
+	Short: "Bring encrypted files outside all scopes under a scope",
+	Long: `Add each file's path to the patterns of the given scope, so that files
+left behind by a pattern edit or encrypted with plain sops become managed.
+'sistry plan' and 'sistry check' list such files.
+
+Like the scope commands, adopt does not re-encrypt files. Run 'sistry apply'
+afterwards; it needs a private key the files are currently encrypted for.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		service := newSopsManager(adoptSafeCmd)
+		return service.Adopt(adoptSafeCmd.GetStringFlag("scope"), args)
+	},
+}
+
+func init() {
+	adoptSafeCmd = NewSafeCommand(adoptCmd)
+	adoptSafeCmd.RegisterStringFlag("scope", "", "scope to adopt the files into (required)")
+	_ = adoptCmd.MarkFlagRequired("scope") // Error is not critical for flag setup
+
+	rootCmd.AddCommand(adoptCmd)
+}
@@ -37,24 +37,45 @@ func determineGitRequirement(requireClean, noRequireClean, force bool) GitRequir
 var applySafeCmd *SafeCommand
 
 var applyCmd = &cobra.Command{
-	Use:   "apply",
+	Use:   "apply [planfile]",
 	Short: "Apply planned changes to SOPS files",
 	Long: `Execute the planned changes atomically. This command will:
 - Verify git working tree is clean (unless --force is used)
 - Apply all changes in a single transaction
-- Rollback on first failure to maintain consistency`,
-	RunE: func(_ *cobra.Command, _ []string) error {
+- Rollback on first failure to maintain consistency
+
+Files already encrypted for exactly their scope's members are not rewritten.
+Use --rotate-data-keys to re-encrypt every file with a fresh data key.
+
+Given a plan file saved with 'sistry plan --out', exactly the saved actions
+are executed. The plan is refused if sopsistry.yaml or any planned file
+changed since it was saved.
+
+With --json, a per-file result document is written instead; --yes is
+required since no confirmation prompt can be shown.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
 		// Guaranteed safe flag access - no errors possible
-		sopsPath := applySafeCmd.GetStringFlag("sops-path")
 		requireCleanGit := applySafeCmd.GetBoolFlag("require-clean-git")
 		noRequireCleanGit := applySafeCmd.GetBoolFlag("no-require-clean-git")
 		force := applySafeCmd.GetBoolFlag("force")
 		yes := applySafeCmd.GetBoolFlag("yes")
+		rotateDataKeys := applySafeCmd.GetBoolFlag("rotate-data-keys")
+
+		var planFile string
+		if len(args) > 0 {
+			planFile = args[0]
+		}
 
 		gitRequirement := determineGitRequirement(requireCleanGit, noRequireCleanGit, force)
 
-		service := core.NewSopsManager(sopsPath)
-		return service.Apply(gitRequirement.requiresCleanGit(), yes)
+		service := newSopsManager(applySafeCmd)
+		return service.Apply(core.ApplyOptions{
+			PlanFile:         planFile,
+			PlanOptions:      core.PlanOptions{RotateDataKeys: rotateDataKeys},
+			RequireCleanGit:  gitRequirement.requiresCleanGit(),
+			SkipConfirmation: yes,
+		})
 	},
 }
 
@@ -63,6 +84,7 @@ func init() {
 	applySafeCmd = NewSafeCommand(applyCmd)
 	applySafeCmd.RegisterBoolFlag("no-require-clean-git", false, "skip git clean check")
 	applySafeCmd.RegisterBoolFlag("force", false, "skip git clean check")
+	applySafeCmd.RegisterBoolFlag("rotate-data-keys", false, "re-encrypt up-to-date files with a fresh data key")
 
 	rootCmd.AddCommand(applyCmd)
 }
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/edvardm/sopsistry/internal/core"
@@ -14,10 +15,21 @@ var checkCmd = &cobra.Command{
 	Short: "Check SOPS configuration and key expiry status",
 	Long: `Check for existing SOPS configuration, team compatibility, and key expiry status.
 This command helps identify potential conflicts between existing .sops.yaml
-files and team-managed encryption settings, and warns about expired or expiring keys.`,
+files and team-managed encryption settings, warns about expired or expiring keys,
+reports whether each file matched by a scope is fully, partially or not encrypted,
+and lists SOPS-encrypted files that no scope matches. Exits with code 4 if any
+member's key has expired.
+
+Use --verbose to also show which local private key file belongs to each member.
+
+With --drift, only compare the recipients of every managed file with the
+manifest, exiting with code 8 if any file still includes removed members or
+is missing new ones. No private key is needed, so this suits CI pipelines.`,
 	RunE: func(_ *cobra.Command, _ []string) error {
-		sopsPath := checkSafeCmd.GetStringFlag("sops-path")
-		verbose := checkSafeCmd.GetBoolFlag("verbose")
+		jsonOutput := checkSafeCmd.GetBoolFlag("json")
+		if checkSafeCmd.GetBoolFlag("drift") {
+			return newSopsManager(checkSafeCmd).CheckDrift()
+		}
 
 		// Check SOPS configuration compatibility
 		detector := core.NewSOPSDetector()
@@ -26,52 +38,78 @@ files and team-managed encryption settings, and warns about expired or expiring
 			return fmt.Errorf("failed to detect SOPS configuration: %w", err)
 		}
 
+		reporter := newReporter(checkSafeCmd)
+		service := newSopsManager(checkSafeCmd).WithReporter(reporter)
+		if jsonOutput {
+			return service.CheckReport(sopsInfo)
+		}
+
 		if !sopsInfo.Exists {
-			fmt.Printf("✅ No existing SOPS configuration detected\n")
-			fmt.Printf("   Team management will work without conflicts\n")
+			reporter.Infof("✅ No existing SOPS configuration detected")
+			reporter.Infof("   Team management will work without conflicts")
 		} else {
-			fmt.Printf("📋 SOPS Configuration Analysis:\n\n")
-			fmt.Printf("  Found: %s\n", sopsInfo.ConfigPath)
+			reporter.Infof("📋 SOPS Configuration Analysis:\n")
+			reporter.Infof("  Found: %s", sopsInfo.ConfigPath)
 
 			if sopsInfo.HasCreationRules {
-				fmt.Printf("⚙️  Has creation rules\n")
+				reporter.Infof("⚙️  Has creation rules")
 			}
 			if sopsInfo.HasAgeKeys {
-				fmt.Printf("🔑 Contains age keys\n")
+				reporter.Infof("🔑 Contains age keys")
 			}
 			if sopsInfo.HasKMSKeys {
-				fmt.Printf("☁️  Contains KMS keys\n")
+				reporter.Infof("☁️  Contains KMS keys")
 			}
 			if sopsInfo.HasPGPKeys {
-				fmt.Printf("🔐 Contains PGP keys\n")
+				reporter.Infof("🔐 Contains PGP keys")
 			}
 
-			fmt.Printf("\n")
+			reporter.Infof("")
 
 			if sopsInfo.ShouldWarn() {
-				fmt.Printf("%s\n", sopsInfo.GetWarningMessage())
+				reporter.Warnf("%s", sopsInfo.GetWarningMessage())
 			} else {
-				fmt.Printf("✅ Configuration looks compatible with team management\n")
+				reporter.Infof("✅ Configuration looks compatible with team management")
 			}
 
-			fmt.Printf("\n%s\n", sopsInfo.GetCoexistenceAdvice())
+			reporter.Infof("\n%s", sopsInfo.GetCoexistenceAdvice())
 		}
 
 		// Check key expiry status
-		fmt.Printf("\n🔑 Key Expiry Status:\n")
-		service := core.NewSopsManager(sopsPath)
-		if err := service.CheckKeyExpiry(verbose); err != nil {
-			// Don't fail the whole command if key checking fails
-			fmt.Printf("❌ Failed to check key expiry: %v\n", err)
+		// Expired keys fail the command once every section is reported;
+		// other failures only warn, so the remaining sections still run
+		reporter.Infof("\n🔑 Key Expiry Status:")
+		var expiryErr error
+		var keyErr *core.KeyError
+		if err := service.CheckKeyExpiry(); errors.As(err, &keyErr) {
+			expiryErr = err
+		} else if err != nil {
+			reporter.Warnf("❌ Failed to check key expiry: %v", err)
+		}
+
+		reporter.Infof("\n⏳ Time-Limited Access:")
+		if err := service.CheckAccessGrants(); err != nil {
+			reporter.Warnf("❌ Failed to check time-limited access: %v", err)
+		}
+
+		reporter.Infof("\n📁 Managed Files:")
+		if err := service.CheckManagedFiles(); err != nil {
+			reporter.Warnf("❌ Failed to check managed files: %v", err)
+		}
+
+		reporter.Infof("\n📦 Encrypted Files Outside Scopes:")
+		if err := service.CheckOrphanedFiles(); err != nil {
+			reporter.Warnf("❌ Failed to look for encrypted files outside scopes: %v", err)
 		}
 
-		return nil
+		return expiryErr
 	},
 }
 
 func init() {
 	checkSafeCmd = NewSafeCommand(checkCmd)
-	checkSafeCmd.RegisterBoolFlag("verbose", false, "show detailed key mapping information")
+	checkSafeCmd.RegisterBoolFlag("drift", false, "only check that encrypted files match the manifest; exit 8 if not")
+	// Uses persistent flags from root: sops-path, json, verbose
 
 	rootCmd.AddCommand(checkCmd)
 }
@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"github.com/edvardm/sopsistry/internal/core"
 	"github.com/spf13/cobra"
 )
 
@@ -13,13 +12,13 @@ var decryptCmd = &cobra.Command{
 	Short:   "Decrypt a SOPS-encrypted file",
 	Long: `Decrypt a SOPS-encrypted file using your local age key.
 By default outputs to stdout. Use --in-place to decrypt the file directly.`,
-	Args: cobra.ExactArgs(1),
+	Args:    cobra.ExactArgs(1),
+	PreRunE: rejectJSON,
 	RunE: func(_ *cobra.Command, args []string) error {
 		filePath := args[0]
-		sopsPath := decryptSafeCmd.GetStringFlag("sops-path")
 		inPlace := decryptSafeCmd.GetBoolFlag("in-place")
 
-		service := core.NewSopsManager(sopsPath)
+		service := newSopsManager(decryptSafeCmd)
 		return service.DecryptFile(filePath, inPlace)
 	},
 }
@@ -3,7 +3,6 @@ package cmd
 import (
 	"fmt"
 
-	"github.com/edvardm/sopsistry/internal/core"
 	"github.com/spf13/cobra"
 )
 
@@ -14,21 +13,25 @@ var encryptCmd = &cobra.Command{
 	Aliases: []string{"enc"},
 	Short:   "Encrypt a file with SOPS using team configuration",
 	Long: `Encrypt a file using the current team configuration.
-The file will be encrypted in-place using age keys from the team manifest.
+The file is matched against scope patterns and encrypted in-place for the
+members of the matching scope only. Files matching no scope are refused
+unless --scope names one explicitly.
 
 Examples:
   st encrypt .env                            # Encrypt entire file
   st encrypt --regex '^(password|key)' .env # Encrypt only matching fields (partial)
   st encrypt --iregex '^(password|key)' .env # Case-insensitive partial encryption
-  st encrypt --regex '.*secret.*' config.yaml # Encrypt fields containing 'secret'`,
-	Args: cobra.ExactArgs(1),
+  st encrypt --regex '.*secret.*' config.yaml # Encrypt fields containing 'secret'
+  st encrypt --scope production prod.env     # Encrypt for an explicitly chosen scope`,
+	Args:    cobra.ExactArgs(1),
+	PreRunE: rejectJSON,
 	RunE: func(_ *cobra.Command, args []string) error {
 		filePath := args[0]
 
-		sopsPath := encryptSafeCmd.GetStringFlag("sops-path")
 		inPlace := encryptSafeCmd.GetBoolFlag("in-place")
 		regex := encryptSafeCmd.GetStringFlag("regex")
 		iregex := encryptSafeCmd.GetStringFlag("iregex")
+		scope := encryptSafeCmd.GetStringFlag("scope")
 
 		// Check that only one of regex or iregex is provided
 		if regex != "" && iregex != "" {
@@ -40,8 +43,8 @@ Examples:
 			regex = "(?i)" + iregex
 		}
 
-		service := core.NewSopsManager(sopsPath)
-		return service.EncryptFile(filePath, inPlace, regex)
+		service := newSopsManager(encryptSafeCmd)
+		return service.EncryptFile(filePath, scope, inPlace, regex)
 	},
 }
 
@@ -50,6 +53,7 @@ func init() {
 	encryptSafeCmd.RegisterBoolFlag("in-place", true, "encrypt file in-place")
 	encryptSafeCmd.RegisterStringFlag("regex", "", "encrypt only fields matching this regex (partial encryption)")
 	encryptSafeCmd.RegisterStringFlag("iregex", "", "encrypt only fields matching this case-insensitive regex (partial encryption)")
+	encryptSafeCmd.RegisterStringFlag("scope", "", "encrypt for this scope instead of the one matching the file")
 
 	rootCmd.AddCommand(encryptCmd)
 }
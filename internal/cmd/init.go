@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"github.com/edvardm/sopsistry/internal/core"
 	"github.com/spf13/cobra"
 )
 
@@ -19,11 +18,11 @@ This command will:
 
 Use --force to overwrite existing configuration files. The .secrets directory and 
 any existing age keys will be preserved.`,
+	PreRunE: rejectJSON,
 	RunE: func(_ *cobra.Command, _ []string) error {
-		sopsPath := initSafeCmd.GetStringFlag("sops-path")
 		force := initSafeCmd.GetBoolFlag("force")
 
-		service := core.NewSopsManager(sopsPath)
+		service := newSopsManager(initSafeCmd)
 		return service.Init(force)
 	},
 }
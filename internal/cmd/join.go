@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var joinSafeCmd *SafeCommand
+var approveSafeCmd *SafeCommand
+var rejectSafeCmd *SafeCommand
+
+var joinCmd = &cobra.Command{
+	Use:   "join",
+	Short: "Ask to be added to the team",
+	Long: `Ask to join the team as the current user, for the scopes given with
+--scope or the default_scopes of sopsistry.yaml. An age key is set up as by
+'sistry init', and a request is written to .sopsistry/requests/<id>.yaml,
+signed with your SSH key: --ssh-key, git's user.signingkey when git signs with
+SSH, or the first of ~/.ssh/id_ed25519, id_ecdsa and id_rsa.
+
+Commit the request and ask an admin to run 'sistry approve <id>'. Be ready to
+confirm your age key and SSH key fingerprint with them: anyone who can commit
+could sign a request with a key of their own.`,
+	Args: cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		service := newSopsManager(joinSafeCmd)
+		return service.Join(joinSafeCmd.GetStringFlag("ssh-key"), joinSafeCmd.GetStringSliceFlag("scope")...)
+	},
+}
+
+var approveCmd = &cobra.Command{
+	Use:   "approve <id>",
+	Short: "Approve a pending join request",
+	Long: `Approve the join request written by 'sistry join'. Requests whose signature
+does not match are refused. The full age key of the request and the fingerprint
+of the SSH key that signed it are shown first: confirm with the requester,
+outside the repository, that both are theirs, since anyone who can commit could
+have written the request. --yes skips the prompt, and is required with --json.
+
+Only an active member can approve: the approver is the member whose private
+key is in .secrets on this machine, not the current user name. The requester
+is added to sopsistry.yaml with a record of who approved them, the request file
+is removed, and the resulting plan is shown. Use
+'sistry apply' to re-encrypt files for the new member.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		service := newSopsManager(approveSafeCmd)
+		return service.Approve(args[0], approveSafeCmd.GetBoolFlag("yes"))
+	},
+}
+
+var rejectCmd = &cobra.Command{
+	Use:   "reject <id>",
+	Short: "Reject a pending join request",
+	Long:  `Reject the join request written by 'sistry join', removing the request file.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		service := newSopsManager(rejectSafeCmd)
+		return service.Reject(args[0])
+	},
+}
+
+func init() {
+	joinSafeCmd = NewSafeCommand(joinCmd)
+	joinSafeCmd.RegisterStringSliceFlag("scope", nil, "scope to ask access to (repeatable; overrides default_scopes)")
+	joinSafeCmd.RegisterStringFlag("ssh-key", "", "SSH key to sign the request with (private key, or public key held by ssh-agent)")
+
+	approveSafeCmd = NewSafeCommand(approveCmd)
+	rejectSafeCmd = NewSafeCommand(rejectCmd)
+
+	rootCmd.AddCommand(joinCmd, approveCmd, rejectCmd)
+}
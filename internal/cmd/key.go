@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var keyAddSafeCmd *SafeCommand
+
+var keyCmd = &cobra.Command{
+	Use:   "key",
+	Short: "Manage a member's device keys",
+	Long: `Manage the age keys of a member's devices. A member's age_key in
+sopsistry.yaml is their first key; every other device (a second laptop, a
+desktop, a CI runner) gets its own labeled key, so private keys never have
+to be copied between machines. Files are encrypted for all of them.`,
+}
+
+var keyAddCmd = &cobra.Command{
+	Use:   "add --label <label>",
+	Short: "Register another device's key for a member",
+	Long: `Register an additional age key for a member, defaulting to the
+current user. Without --key, the private key on this machine is used if it is
+not registered yet, and a new one is generated otherwise.
+
+Like add-member, this does not re-encrypt files. Use 'sistry plan' and
+'sistry apply' to encrypt files for the new key.`,
+	Args: cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		service := newSopsManager(keyAddSafeCmd)
+		return service.AddMemberKey(
+			keyAddSafeCmd.GetStringFlag("member"),
+			keyAddSafeCmd.GetStringFlag("label"),
+			keyAddSafeCmd.GetStringFlag("key"))
+	},
+}
+
+func init() {
+	keyAddSafeCmd = NewSafeCommand(keyAddCmd)
+	keyAddSafeCmd.RegisterStringFlag("label", "", "name of the device, e.g. desktop (required)")
+	_ = keyAddCmd.MarkFlagRequired("label") // Error is not critical for flag setup
+	keyAddSafeCmd.RegisterStringFlag("key", "", "age public key of the device (default: this machine's key)")
+	keyAddSafeCmd.RegisterStringFlag("member", "", "member to add the key to (default: current user)")
+
+	keyCmd.AddCommand(keyAddCmd)
+	rootCmd.AddCommand(keyCmd)
+}
@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"github.com/edvardm/sopsistry/internal/core"
 	"github.com/spf13/cobra"
 )
 
@@ -13,18 +12,21 @@ var listCmd = &cobra.Command{
 	Long: `Display current team configuration including:
 - Team members and their age keys
 - Encrypted files under management
-- Current scope assignments`,
+- Current scope assignments
+
+Members can be filtered by label with --label team=payments, or --label team
+for any value; every given label must match.`,
 	RunE: func(_ *cobra.Command, _ []string) error {
-		sopsPath := listSafeCmd.GetStringFlag("sops-path")
 		jsonOutput := listSafeCmd.GetBoolFlag("json")
 
-		service := core.NewSopsManager(sopsPath)
-		return service.List(jsonOutput)
+		service := newSopsManager(listSafeCmd)
+		return service.List(jsonOutput, listSafeCmd.GetStringSliceFlag("label")...)
 	},
 }
 
 func init() {
 	listSafeCmd = NewSafeCommand(listCmd)
+	listSafeCmd.RegisterStringSliceFlag("label", nil, "only list members with this label, as key=value or key (repeatable)")
 	// Uses persistent flags from root: sops-path, json
 
 	rootCmd.AddCommand(listCmd)
@@ -3,18 +3,21 @@ package cmd
 import (
 	"fmt"
 
-	"github.com/edvardm/sopsistry/internal/core"
 	"github.com/spf13/cobra"
 )
 
 var addMemberSafeCmd *SafeCommand
 var removeMemberSafeCmd *SafeCommand
+var memberAddMachineSafeCmd *SafeCommand
+var memberSuspendSafeCmd *SafeCommand
+var memberResumeSafeCmd *SafeCommand
 
 var addMemberCmd = &cobra.Command{
 	Use:     "add-member <id>",
 	Aliases: []string{"add"},
 	Short:   "Add a team member",
-	Long: `Add a new team member to the default scope.
+	Long: `Add a new team member to the scopes given with --scope, or to the
+default_scopes listed in sopsistry.yaml if no --scope is given.
 This command updates the team configuration but does not immediately
 re-encrypt files. Use 'st plan' and 'st apply' to see and execute changes.`,
 	Args: cobra.ExactArgs(1),
@@ -26,9 +29,8 @@ re-encrypt files. Use 'st plan' and 'st apply' to see and execute changes.`,
 			return fmt.Errorf("--key flag is required")
 		}
 
-		sopsPath := addMemberSafeCmd.GetStringFlag("sops-path")
-		service := core.NewSopsManager(sopsPath)
-		return service.AddMember(memberID, ageKey)
+		service := newSopsManager(addMemberSafeCmd)
+		return service.AddMember(memberID, ageKey, addMemberSafeCmd.GetStringSliceFlag("scope")...)
 	},
 }
 
@@ -43,19 +45,80 @@ re-encrypt files. Use 'st plan' and 'st apply' to see and execute changes.`,
 	RunE: func(_ *cobra.Command, args []string) error {
 		memberID := args[0]
 
-		sopsPath := removeMemberSafeCmd.GetStringFlag("sops-path")
-		service := core.NewSopsManager(sopsPath)
+		service := newSopsManager(removeMemberSafeCmd)
 		return service.RemoveMember(memberID)
 	},
 }
 
+var memberCmd = &cobra.Command{
+	Use:   "member",
+	Short: "Manage team members",
+	Long: `Manage team members. People are added with add-member; machine members
+such as CI runners and deploy bots are added with 'member add-machine'.
+Members on leave can be suspended and resumed without losing their setup.`,
+}
+
+var memberAddMachineCmd = &cobra.Command{
+	Use:   "add-machine <id>",
+	Short: "Add a machine member with a generated key pair",
+	Long: `Add a machine member, such as a CI runner or deploy bot, to the scopes
+given with --scope. Machine members never join default_scopes and do not
+inherit access through inherits_members_from; their keys expire after
+settings.max_machine_key_age_days (90 by default).
+
+A new age key pair is generated. The private key is printed once and not
+saved: paste it into the CI system's secret store, e.g. as SOPS_AGE_KEY.
+Use 'sistry plan' and 'sistry apply' to re-encrypt files for the new member.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		service := newSopsManager(memberAddMachineSafeCmd)
+		return service.AddMachine(args[0], memberAddMachineSafeCmd.GetStringSliceFlag("scope"))
+	},
+}
+
+var memberSuspendCmd = &cobra.Command{
+	Use:   "suspend <id>",
+	Short: "Temporarily revoke a member's access",
+	Long: `Suspend a member, e.g. during long leave. Unlike remove-member, their ID,
+key, creation date and scope memberships are kept, but they are left out of
+every recipient set until resumed. Use 'sistry plan' and 'sistry apply' to
+re-encrypt files without them.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		service := newSopsManager(memberSuspendSafeCmd)
+		return service.SuspendMember(args[0])
+	},
+}
+
+var memberResumeCmd = &cobra.Command{
+	Use:   "resume <id>",
+	Short: "Restore a suspended member's access",
+	Long: `Resume a suspended member, restoring their access to the scopes they
+belong to. Use 'sistry plan' and 'sistry apply' to re-encrypt files for them.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		service := newSopsManager(memberResumeSafeCmd)
+		return service.ResumeMember(args[0])
+	},
+}
+
 func init() {
 	addMemberSafeCmd = NewSafeCommand(addMemberCmd)
 	addMemberSafeCmd.RegisterStringFlag("key", "", "age public key for the member (required)")
 	_ = addMemberCmd.MarkFlagRequired("key") // Error is not critical for flag setup
+	addMemberSafeCmd.RegisterStringSliceFlag("scope", nil, "scope to grant the member access to (repeatable; overrides default_scopes)")
 
 	removeMemberSafeCmd = NewSafeCommand(removeMemberCmd)
 
+	memberAddMachineSafeCmd = NewSafeCommand(memberAddMachineCmd)
+	memberAddMachineSafeCmd.RegisterStringSliceFlag("scope", nil, "scope the machine can read (repeatable, at least one required)")
+	_ = memberAddMachineCmd.MarkFlagRequired("scope") // Error is not critical for flag setup
+
+	memberSuspendSafeCmd = NewSafeCommand(memberSuspendCmd)
+	memberResumeSafeCmd = NewSafeCommand(memberResumeCmd)
+
+	memberCmd.AddCommand(memberAddMachineCmd, memberSuspendCmd, memberResumeCmd)
 	rootCmd.AddCommand(addMemberCmd)
 	rootCmd.AddCommand(removeMemberCmd)
+	rootCmd.AddCommand(memberCmd)
 }
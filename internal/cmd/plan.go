@@ -14,19 +14,38 @@ var planCmd = &cobra.Command{
 based on the current team configuration. This is a dry-run that shows:
 - Which files will be re-encrypted
 - What recipients will be added or removed
-- Any validation errors or warnings`,
+- Which files are already up to date
+- Any validation errors or warnings
+
+Files whose recipients already match their scope are left untouched.
+Use --rotate-data-keys to re-encrypt them anyway with a fresh data key.
+
+With --out, the plan is also saved together with hashes of sopsistry.yaml
+and every planned file, so 'sistry apply <planfile>' can execute exactly
+this plan later and refuse it if anything changed in between.
+
+With --detailed-exitcode, the exit code is 0 when nothing would change
+and 2 when the plan has changes.`,
 	RunE: func(_ *cobra.Command, _ []string) error {
-		sopsPath := planSafeCmd.GetStringFlag("sops-path")
-		noColor := planSafeCmd.GetBoolFlag("no-color")
+		rotateDataKeys := planSafeCmd.GetBoolFlag("rotate-data-keys")
+		outFile := planSafeCmd.GetStringFlag("out")
+		detailedExitCode := planSafeCmd.GetBoolFlag("detailed-exitcode")
 
-		service := core.NewSopsManager(sopsPath)
-		return service.Plan(noColor)
+		service := newSopsManager(planSafeCmd)
+		return service.Plan(core.PlanOptions{
+			OutFile:          outFile,
+			RotateDataKeys:   rotateDataKeys,
+			DetailedExitCode: detailedExitCode,
+		})
 	},
 }
 
 func init() {
 	planSafeCmd = NewSafeCommand(planCmd)
-	// Uses persistent flags from root: sops-path, no-color
+	planSafeCmd.RegisterBoolFlag("rotate-data-keys", false, "re-encrypt up-to-date files with a fresh data key")
+	planSafeCmd.RegisterStringFlag("out", "", "save the plan to this file for 'sistry apply <planfile>'")
+	planSafeCmd.RegisterBoolFlag("detailed-exitcode", false, "exit with 2 when the plan has changes")
+	// Uses persistent flags from root: sops-path, no-color, json, quiet, verbose, debug
 
 	rootCmd.AddCommand(planCmd)
 }
@@ -0,0 +1,31 @@
+package cmd
+
+import "github.com/edvardm/sopsistry/internal/core"
+
+// newReporter builds a reporter from the persistent output flags.
+// --json implies quiet so that only the JSON document reaches stdout.
+func newReporter(sc *SafeCommand) core.Reporter {
+	level := core.LevelNormal
+	switch {
+	case sc.GetBoolFlag("json"):
+		level = core.LevelQuiet
+	case sc.GetBoolFlag("debug"):
+		level = core.LevelDebug
+	case sc.GetBoolFlag("verbose"):
+		level = core.LevelVerbose
+	case sc.GetBoolFlag("quiet"):
+		level = core.LevelQuiet
+	}
+
+	return core.NewConsoleReporter(core.ReporterOptions{
+		Level:   level,
+		NoColor: sc.GetBoolFlag("no-color") || sc.GetBoolFlag("json"),
+	})
+}
+
+// newSopsManager creates a manager honouring the persistent sops-path and output flags
+func newSopsManager(sc *SafeCommand) *core.SopsManager {
+	return core.NewSopsManager(sc.GetStringFlag("sops-path")).
+		WithReporter(newReporter(sc)).
+		WithJSONOutput(sc.GetBoolFlag("json"))
+}
@@ -3,7 +3,9 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/edvardm/sopsistry/internal/core"
 	"github.com/spf13/cobra"
 )
 
@@ -20,15 +22,63 @@ var rootCmd = &cobra.Command{
 Provides standardized workflows for team member onboarding/offboarding,
 key rotation, and encrypted file management.`,
 	Version: fmt.Sprintf("%s (commit: %s, built: %s)", version, commit, date),
+	// Flags and arguments are parsed by now, so later errors are not usage errors
+	PersistentPreRun: func(cmd *cobra.Command, _ []string) {
+		cmd.SilenceUsage = true
+	},
+	SilenceErrors: true,
 }
 
-// Execute runs the root command and returns any error
+// Execute runs the root command and returns any error.
+// With --json, a failure is reported as a JSON document on stdout.
+// Outcomes such as a cancelled prompt are not reported as errors.
 func Execute() error {
-	return rootCmd.Execute()
+	cmd, err := rootCmd.ExecuteC()
+	if err == nil || core.IsOutcome(err) {
+		return err
+	}
+
+	switch {
+	case jsonRequested(cmd) && !core.IsJSONReported(err):
+		_ = core.WriteJSONError(os.Stdout, jsonCommandName(cmd), err) //nolint:errcheck // Already failing, nothing more to report
+	case !jsonRequested(cmd):
+		cmd.PrintErrln("Error:", err)
+	}
+	return err
+}
+
+// ExitCode returns the process exit code for an error returned by Execute
+func ExitCode(err error) int {
+	return core.ExitCode(err)
+}
+
+func jsonRequested(cmd *cobra.Command) bool {
+	jsonOutput, err := cmd.Flags().GetBool("json")
+	return err == nil && jsonOutput
+}
+
+// jsonCommandName names cmd in JSON error documents like its results, e.g.
+// "scope add-member", or "check-drift" for 'check --drift'
+func jsonCommandName(cmd *cobra.Command) string {
+	if drift, err := cmd.Flags().GetBool("drift"); err == nil && drift && cmd.Name() == "check" {
+		return "check-drift"
+	}
+	return strings.TrimPrefix(cmd.CommandPath(), rootCmd.Name()+" ")
+}
+
+// rejectJSON is the PreRunE of commands whose output is not a JSON document
+func rejectJSON(cmd *cobra.Command, _ []string) error {
+	if jsonRequested(cmd) {
+		return fmt.Errorf("--json is not supported by %s", cmd.Name())
+	}
+	return nil
 }
 
 func init() {
-	rootCmd.PersistentFlags().Bool("no-color", false, "disable colored output")
+	rootCmd.PersistentFlags().Bool("no-color", false, "disable colored output and emoji")
+	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "only print primary output and warnings")
+	rootCmd.PersistentFlags().Bool("verbose", false, "print additional detail")
+	rootCmd.PersistentFlags().Bool("debug", false, "print external commands as they are run")
 	rootCmd.PersistentFlags().Bool("json", false, "output in JSON format")
 	rootCmd.PersistentFlags().String("sops-path", "sops", "path to sops binary")
 	rootCmd.PersistentFlags().Bool("require-clean-git", true, "require clean git working tree")
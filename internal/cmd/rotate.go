@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"github.com/edvardm/sopsistry/internal/core"
 	"github.com/spf13/cobra"
 )
 
@@ -20,11 +19,11 @@ This command will:
 - Backup and restore on failure
 
 Use --force to skip age validation and rotate immediately.`,
+	PreRunE: rejectJSON,
 	RunE: func(_ *cobra.Command, _ []string) error {
-		sopsPath := rotateSafeCmd.GetStringFlag("sops-path")
 		force := rotateSafeCmd.GetBoolFlag("force")
 
-		service := core.NewSopsManager(sopsPath)
+		service := newSopsManager(rotateSafeCmd)
 		return service.RotateKey(force)
 	},
 }
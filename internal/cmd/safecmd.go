@@ -9,16 +9,18 @@ import (
 // SafeCommand wraps cobra.Command with guaranteed flag registration
 type SafeCommand struct {
 	*cobra.Command
-	stringFlags map[string]bool
-	boolFlags   map[string]bool
+	stringFlags      map[string]bool
+	stringSliceFlags map[string]bool
+	boolFlags        map[string]bool
 }
 
 // NewSafeCommand creates a command with guaranteed flag tracking
 func NewSafeCommand(cmd *cobra.Command) *SafeCommand {
 	return &SafeCommand{
-		Command:     cmd,
-		stringFlags: make(map[string]bool),
-		boolFlags:   make(map[string]bool),
+		Command:          cmd,
+		stringFlags:      make(map[string]bool),
+		stringSliceFlags: make(map[string]bool),
+		boolFlags:        make(map[string]bool),
 	}
 }
 
@@ -28,6 +30,12 @@ func (sc *SafeCommand) RegisterStringFlag(name, defaultVal, usage string) {
 	sc.stringFlags[name] = true
 }
 
+// RegisterStringSliceFlag registers and tracks a repeatable string flag
+func (sc *SafeCommand) RegisterStringSliceFlag(name string, defaultVal []string, usage string) {
+	sc.Command.Flags().StringSlice(name, defaultVal, usage)
+	sc.stringSliceFlags[name] = true
+}
+
 // RegisterBoolFlag registers and tracks a boolean flag
 func (sc *SafeCommand) RegisterBoolFlag(name string, defaultVal bool, usage string) {
 	sc.Command.Flags().Bool(name, defaultVal, usage)
@@ -43,6 +51,15 @@ func (sc *SafeCommand) GetStringFlag(name string) string {
 	return value
 }
 
+// GetStringSliceFlag safely retrieves a registered repeatable string flag
+func (sc *SafeCommand) GetStringSliceFlag(name string) []string {
+	value, err := sc.Command.Flags().GetStringSlice(name)
+	if err != nil {
+		panic(fmt.Sprintf("PROGRAMMING ERROR: flag '%s' not accessible: %v", name, err))
+	}
+	return value
+}
+
 // GetBoolFlag safely retrieves a registered boolean flag (including persistent flags)
 func (sc *SafeCommand) GetBoolFlag(name string) bool {
 	value, err := sc.Command.Flags().GetBool(name)
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var scopeAddSafeCmd *SafeCommand
+var scopeRemoveSafeCmd *SafeCommand
+var scopeRenameSafeCmd *SafeCommand
+var scopeAddMemberSafeCmd *SafeCommand
+var scopeRemoveMemberSafeCmd *SafeCommand
+var scopeAddPatternSafeCmd *SafeCommand
+var scopeRemovePatternSafeCmd *SafeCommand
+
+var scopeCmd = &cobra.Command{
+	Use:   "scope",
+	Short: "Manage scopes",
+	Long: `Create, remove and rename scopes, and change which members and file
+patterns they include. Every change is validated before sopsistry.yaml is
+written, and the resulting plan is summarized afterwards.
+
+Like add-member, these commands do not re-encrypt files. Use 'sistry plan'
+and 'sistry apply' to see and execute changes.`,
+}
+
+var scopeAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Create a scope",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		service := newSopsManager(scopeAddSafeCmd)
+		return service.AddScope(args[0],
+			scopeAddSafeCmd.GetStringSliceFlag("pattern"),
+			scopeAddSafeCmd.GetStringSliceFlag("member"))
+	},
+}
+
+var scopeRemoveCmd = &cobra.Command{
+	Use:     "remove <name>",
+	Aliases: []string{"rm"},
+	Short:   "Remove a scope",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		service := newSopsManager(scopeRemoveSafeCmd)
+		return service.RemoveScope(args[0])
+	},
+}
+
+var scopeRenameCmd = &cobra.Command{
+	Use:   "rename <old-name> <new-name>",
+	Short: "Rename a scope, keeping its members and patterns",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(_ *cobra.Command, args []string) error {
+		service := newSopsManager(scopeRenameSafeCmd)
+		return service.RenameScope(args[0], args[1])
+	},
+}
+
+var scopeAddMemberCmd = &cobra.Command{
+	Use:   "add-member <scope> <member-id|@group>",
+	Short: "Grant a team member, or a group of members, access to a scope",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(_ *cobra.Command, args []string) error {
+		service := newSopsManager(scopeAddMemberSafeCmd)
+		if until := scopeAddMemberSafeCmd.GetStringFlag("until"); until != "" {
+			return service.GrantScopeAccess(args[0], args[1], until)
+		}
+		return service.AddScopeMember(args[0], args[1])
+	},
+}
+
+var scopeRemoveMemberCmd = &cobra.Command{
+	Use:   "remove-member <scope> <member-id|@group>",
+	Short: "Revoke a team member's access to a scope",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(_ *cobra.Command, args []string) error {
+		service := newSopsManager(scopeRemoveMemberSafeCmd)
+		return service.RemoveScopeMember(args[0], args[1])
+	},
+}
+
+var scopeAddPatternCmd = &cobra.Command{
+	Use:   "add-pattern <scope> <pattern>",
+	Short: "Add a file pattern to a scope",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(_ *cobra.Command, args []string) error {
+		service := newSopsManager(scopeAddPatternSafeCmd)
+		return service.AddScopePattern(args[0], args[1])
+	},
+}
+
+var scopeRemovePatternCmd = &cobra.Command{
+	Use:   "remove-pattern <scope> <pattern>",
+	Short: "Remove a file pattern from a scope",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(_ *cobra.Command, args []string) error {
+		service := newSopsManager(scopeRemovePatternSafeCmd)
+		return service.RemoveScopePattern(args[0], args[1])
+	},
+}
+
+func init() {
+	scopeAddSafeCmd = NewSafeCommand(scopeAddCmd)
+	scopeAddSafeCmd.RegisterStringSliceFlag("pattern", nil, "file pattern matched by the scope (repeatable, at least one required)")
+	scopeAddSafeCmd.RegisterStringSliceFlag("member", nil, "member or @group with access to the scope (repeatable)")
+	_ = scopeAddCmd.MarkFlagRequired("pattern") // Error is not critical for flag setup
+
+	scopeRemoveSafeCmd = NewSafeCommand(scopeRemoveCmd)
+	scopeRenameSafeCmd = NewSafeCommand(scopeRenameCmd)
+	scopeAddMemberSafeCmd = NewSafeCommand(scopeAddMemberCmd)
+	scopeAddMemberSafeCmd.RegisterStringFlag("until", "", "last day of access, as YYYY-MM-DD; plan revokes it afterwards")
+	scopeRemoveMemberSafeCmd = NewSafeCommand(scopeRemoveMemberCmd)
+	scopeAddPatternSafeCmd = NewSafeCommand(scopeAddPatternCmd)
+	scopeRemovePatternSafeCmd = NewSafeCommand(scopeRemovePatternCmd)
+
+	scopeCmd.AddCommand(scopeAddCmd, scopeRemoveCmd, scopeRenameCmd,
+		scopeAddMemberCmd, scopeRemoveMemberCmd, scopeAddPatternCmd, scopeRemovePatternCmd)
+	rootCmd.AddCommand(scopeCmd)
+}
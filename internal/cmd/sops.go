@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"github.com/edvardm/sopsistry/internal/core"
 	"github.com/spf13/cobra"
 )
 
@@ -20,10 +19,9 @@ Examples:
 
 You can copy and run the displayed command directly.`,
 	RunE: func(_ *cobra.Command, args []string) error {
-		sopsPath := sopsSafeCmd.GetStringFlag("sops-path")
 		execute := sopsSafeCmd.GetBoolFlag("exec")
 
-		service := core.NewSopsManager(sopsPath)
+		service := newSopsManager(sopsSafeCmd)
 		if execute {
 			return service.ExecuteSOPSCommand(args)
 		}
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var validateSafeCmd *SafeCommand
+
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validate the team manifest",
+	Long: `Check sopsistry.yaml for problems such as duplicate member IDs, invalid
+age keys, scopes referencing unknown members and empty file patterns.
+Every problem is reported at once with its line and column.
+
+The same validation runs whenever a command loads the manifest.`,
+	Args: cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		service := newSopsManager(validateSafeCmd)
+		return service.Validate()
+	},
+}
+
+func init() {
+	validateSafeCmd = NewSafeCommand(validateCmd)
+	// Uses persistent flags from root: json
+
+	rootCmd.AddCommand(validateCmd)
+}
@@ -0,0 +1,90 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Adopt brings files that no scope matches under scope by adding each file's
+// path to the scope's patterns. Like other scope changes, files are only
+// re-encrypted by the next apply.
+func (s *SopsManager) Adopt(scope string, files []string) error {
+	manifest, err := LoadManifest(s.configPath)
+	if err != nil {
+		return fmt.Errorf(FailedToLoadManifestMsg, err)
+	}
+	if manifest.FindScope(scope) == nil {
+		return NewManifestError("validate", "scope "+scope, fmt.Errorf("scope %s not found", scope))
+	}
+
+	_, matches, err := NewPlanner(s.sopsPath).scopeMatches(manifest)
+	if err != nil {
+		return err
+	}
+
+	patterns := make([]string, 0, len(files))
+	for _, file := range files {
+		if _, err := os.Stat(file); err != nil {
+			return fmt.Errorf("cannot adopt %s: %w", file, err)
+		}
+		if matched := matches[relativeToWorkDir(file)]; len(matched) > 0 {
+			return fmt.Errorf("%s is already matched by scope %s", file, strings.Join(scopeNames(matched), ", "))
+		}
+		pattern, err := adoptPattern(file)
+		if err != nil {
+			return err
+		}
+		patterns = append(patterns, pattern)
+		s.warnIfUndecryptable(file, manifest)
+	}
+
+	message := fmt.Sprintf("Adopted %d files into scope %s", len(files), scope)
+	return s.editScopes("adopt", "adopt", scope, message, func(editor *ManifestEditor) error {
+		for _, pattern := range patterns {
+			if err := editor.AddScopePattern(scope, pattern); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// warnIfUndecryptable warns when no team member holds a key file is encrypted
+// for, since apply has to decrypt it before re-encrypting for the scope
+func (s *SopsManager) warnIfUndecryptable(file string, manifest *Manifest) {
+	metadata, err := ReadSOPSMetadata(file)
+	if err != nil || !metadata.IsEncrypted() {
+		return
+	}
+	orphan := newOrphanedFile(file, metadata.AgeRecipients, manifest)
+	if len(orphan.Recipients) == 0 {
+		s.reporter.Warnf("⚠️  %s is not encrypted for any team member (%s); apply needs one of its private keys",
+			file, orphan.describeRecipients())
+	}
+}
+
+// adoptPattern returns a scope pattern matching exactly file
+func adoptPattern(file string) (string, error) {
+	rel := filepath.ToSlash(relativeToWorkDir(file))
+	if rel == ".." || strings.HasPrefix(rel, "../") {
+		return "", fmt.Errorf("cannot adopt %s: it is outside the working directory", file)
+	}
+	if !strings.Contains(rel, "/") {
+		rel = "./" + rel // Anchored, rather than matching the name at any depth
+	}
+	return escapePattern(rel), nil
+}
+
+// escapePattern quotes the characters scope patterns treat specially
+func escapePattern(file string) string {
+	var escaped strings.Builder
+	for i, r := range file {
+		if strings.ContainsRune(`*?[\`, r) || (i == 0 && r == '!') {
+			escaped.WriteRune('\\')
+		}
+		escaped.WriteRune(r)
+	}
+	return escaped.String()
+}
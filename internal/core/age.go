@@ -9,21 +9,35 @@ import (
 )
 
 func (s *SopsManager) generateAgeKey(keyPath string) (string, error) {
+	publicKey, privateKey, err := runAgeKeygen()
+	if err != nil {
+		return "", NewKeyError("generate", keyPath, err)
+	}
+
+	if err := os.WriteFile(keyPath, []byte(privateKey+"\n"), PrivateKeyFileMode); err != nil {
+		return "", NewKeyError("generate", keyPath, fmt.Errorf("failed to write private key: %w", err))
+	}
+
+	s.reporter.Infof("Generated age key pair:")
+	s.reporter.Infof("  Public key:  %s", publicKey)
+	s.reporter.Verbosef("  Private key: %s (saved)", keyPath)
+
+	return publicKey, nil
+}
+
+// runAgeKeygen generates a key pair without writing it anywhere
+func runAgeKeygen() (publicKey, privateKey string, err error) {
 	if err := ensureBinaryAvailable(AgeKeygenBinary, "Please install age: https://github.com/FiloSottile/age"); err != nil {
-		return "", err
+		return "", "", err
 	}
 
 	cmd := exec.Command(AgeKeygenBinary)
 	output, err := cmd.Output()
 	if err != nil {
-		return "", fmt.Errorf("failed to generate age key: %w", err)
+		return "", "", err
 	}
 
-	lines := strings.Split(string(output), "\n")
-	var publicKey string
-	var privateKey string
-
-	for _, line := range lines {
+	for _, line := range strings.Split(string(output), "\n") {
 		line = strings.TrimSpace(line)
 		if after, ok := strings.CutPrefix(line, "# public key: "); ok {
 			publicKey = after
@@ -33,34 +47,25 @@ func (s *SopsManager) generateAgeKey(keyPath string) (string, error) {
 	}
 
 	if privateKey == "" || publicKey == "" {
-		return "", fmt.Errorf("failed to parse age-keygen output")
-	}
-
-	if err := os.WriteFile(keyPath, []byte(privateKey+"\n"), PrivateKeyFileMode); err != nil {
-		return "", fmt.Errorf("failed to write private key: %w", err)
+		return "", "", fmt.Errorf("failed to parse age-keygen output")
 	}
-
-	fmt.Printf("Generated age key pair:\n")
-	fmt.Printf("  Public key:  %s\n", publicKey)
-	fmt.Printf("  Private key: %s (saved)\n", keyPath)
-
-	return publicKey, nil
+	return publicKey, privateKey, nil
 }
 
 func (s *SopsManager) getPublicKeyFromPrivateKey(keyPath string) (string, error) {
 	if err := ensureBinaryAvailable(AgeKeygenBinary, "Please install age: https://github.com/FiloSottile/age"); err != nil {
-		return "", err
+		return "", NewKeyError("read", keyPath, err)
 	}
 
 	cmd := exec.Command(AgeKeygenBinary, "-y", keyPath)
 	output, err := cmd.Output()
 	if err != nil {
-		return "", fmt.Errorf("failed to extract public key from %s: %w", keyPath, err)
+		return "", NewKeyError("read", keyPath, fmt.Errorf("failed to extract public key: %w", err))
 	}
 
 	publicKey := strings.TrimSpace(string(output))
 	if publicKey == "" {
-		return "", fmt.Errorf("failed to extract public key from %s", keyPath)
+		return "", NewKeyError("read", keyPath, fmt.Errorf("failed to extract public key"))
 	}
 
 	return publicKey, nil
@@ -114,9 +114,10 @@ func (b SOPSCommandBuilder[Complete]) Args() []string {
 
 // ManifestBuilder builds manifest configurations with validation
 type ManifestBuilder struct {
-	members  []Member
-	scopes   []Scope
-	settings Settings
+	members       []Member
+	scopes        []Scope
+	defaultScopes []string
+	settings      Settings
 }
 
 // NewManifestBuilder creates a new manifest builder
@@ -142,6 +143,12 @@ func (b *ManifestBuilder) WithScope(scope Scope) *ManifestBuilder {
 	return b
 }
 
+// WithDefaultScopes sets the scopes new members join by default
+func (b *ManifestBuilder) WithDefaultScopes(scopes ...string) *ManifestBuilder {
+	b.defaultScopes = append(b.defaultScopes, scopes...)
+	return b
+}
+
 // WithSettings configures manifest settings
 func (b *ManifestBuilder) WithSettings(settings Settings) *ManifestBuilder {
 	b.settings = settings
@@ -155,9 +162,10 @@ func (b *ManifestBuilder) Build() Result[*Manifest] {
 	}
 
 	manifest := &Manifest{
-		Members:  b.members,
-		Scopes:   b.scopes,
-		Settings: b.settings,
+		Members:       b.members,
+		Scopes:        b.scopes,
+		DefaultScopes: b.defaultScopes,
+		Settings:      b.settings,
 	}
 
 	return Ok(manifest)
@@ -18,6 +18,9 @@ func NewMemberID(id string) (MemberID, error) {
 	if strings.ContainsAny(id, " \t\n\r") {
 		return "", fmt.Errorf("member ID cannot contain whitespace")
 	}
+	if strings.HasPrefix(id, groupPrefix) {
+		return "", fmt.Errorf("member ID cannot start with %q, which marks group references", groupPrefix)
+	}
 	return MemberID(id), nil
 }
 
@@ -26,6 +29,28 @@ func (m MemberID) String() string {
 	return string(m)
 }
 
+// GroupName represents a validated member group identifier
+type GroupName string
+
+// NewGroupName creates a validated group name, given without the leading "@"
+func NewGroupName(name string) (GroupName, error) {
+	if strings.TrimSpace(name) == "" {
+		return "", fmt.Errorf("group name cannot be empty")
+	}
+	if strings.ContainsAny(name, " \t\n\r") {
+		return "", fmt.Errorf("group name cannot contain whitespace")
+	}
+	if strings.HasPrefix(name, groupPrefix) {
+		return "", fmt.Errorf("group name must not start with %q; use it only when referencing the group", groupPrefix)
+	}
+	return GroupName(name), nil
+}
+
+// String returns the underlying string value
+func (g GroupName) String() string {
+	return string(g)
+}
+
 // ScopeName represents a validated scope identifier
 type ScopeName string
 
@@ -46,6 +71,25 @@ func (s ScopeName) String() string {
 	return string(s)
 }
 
+// KeyLabel names one of a member's devices, e.g. "desktop"
+type KeyLabel string
+
+// NewKeyLabel creates a validated key label
+func NewKeyLabel(label string) (KeyLabel, error) {
+	if strings.TrimSpace(label) == "" {
+		return "", fmt.Errorf("key label cannot be empty")
+	}
+	if strings.ContainsAny(label, " \t\n\r/") {
+		return "", fmt.Errorf("key label cannot contain whitespace or '/'")
+	}
+	return KeyLabel(label), nil
+}
+
+// String returns the underlying string value
+func (k KeyLabel) String() string {
+	return string(k)
+}
+
 // AgePublicKey represents a validated age public key
 type AgePublicKey string
 
@@ -0,0 +1,94 @@
+package core
+
+import (
+	"slices"
+	"strings"
+)
+
+// DriftedFile is a managed file whose encryption does not match the manifest,
+// typically because a manifest change was merged without running apply
+type DriftedFile struct {
+	File      string   `json:"file"`
+	Scope     string   `json:"scope"`
+	Missing   []string `json:"missing"`   // Scope members, or their devices' keys, the file is not encrypted for yet
+	Removed   []string `json:"removed"`   // Members outside the scope who can still decrypt it
+	Unknown   []string `json:"unknown"`   // Age keys not in the manifest that can still decrypt it
+	Plaintext bool     `json:"plaintext"` // The file is not encrypted at all
+}
+
+// Drift lists the files whose current recipients, read from their SOPS
+// metadata, differ from their scope's members. Skipped files are ignored.
+func (p *Plan) Drift() []DriftedFile {
+	drifted := []DriftedFile{}
+	for _, action := range p.Actions {
+		switch {
+		case action.Type == ActionEncrypt:
+			drifted = append(drifted, DriftedFile{
+				File: action.File, Scope: action.Scope, Plaintext: true,
+				Missing: action.AddedRecipients, Removed: []string{}, Unknown: []string{},
+			})
+		case action.Type == ActionSkip:
+		case len(action.AddedRecipients)+len(action.AddedKeys)+len(action.RemovedRecipients)+len(action.UnknownRecipients) > 0:
+			drifted = append(drifted, DriftedFile{
+				File: action.File, Scope: action.Scope,
+				Missing: append(slices.Clone(action.AddedRecipients), action.AddedKeys...), Removed: action.RemovedRecipients, Unknown: action.UnknownRecipients,
+			})
+		}
+	}
+	return drifted
+}
+
+// describe renders the mismatch as "still readable by bob; not encrypted for carol"
+func (d DriftedFile) describe() string {
+	if d.Plaintext {
+		return "not encrypted"
+	}
+
+	var problems []string
+	if readers := append(append([]string{}, d.Removed...), MapSlice(d.Unknown, shortenKey)...); len(readers) > 0 {
+		problems = append(problems, "still readable by "+strings.Join(readers, ", "))
+	}
+	if len(d.Missing) > 0 {
+		problems = append(problems, "not encrypted for "+strings.Join(d.Missing, ", "))
+	}
+	return strings.Join(problems, "; ")
+}
+
+// CheckDrift compares the recipients of every managed file with the manifest
+// and returns ErrDrift if any file still needs 'sistry apply'. Only SOPS
+// metadata is read, so no private key is needed.
+func (s *SopsManager) CheckDrift() error {
+	plan, err := s.computePlan(false)
+	if err != nil {
+		return err
+	}
+
+	drifted := plan.Drift()
+	checked := len(Filter(plan.Actions, func(a Action) bool { return a.Type != ActionSkip }))
+
+	if s.jsonOutput {
+		if err := WriteJSONResult(s.reporter.Out(), "check-drift", DriftResult{Files: drifted, Checked: checked}); err != nil {
+			return err
+		}
+	} else {
+		s.printDrift(drifted, checked)
+	}
+
+	if len(drifted) > 0 {
+		return ErrDrift
+	}
+	return nil
+}
+
+func (s *SopsManager) printDrift(drifted []DriftedFile, checked int) {
+	if len(drifted) == 0 {
+		s.reporter.Infof("✅ All %d managed files match the manifest", checked)
+		return
+	}
+
+	s.reporter.Infof("❌ %d of %d managed files do not match the manifest:", len(drifted), checked)
+	for _, file := range drifted {
+		s.reporter.Infof("  %s (%s): %s", file.File, file.Scope, file.describe())
+	}
+	s.reporter.Infof("\nRun 'sistry apply' to re-encrypt them for the current manifest")
+}
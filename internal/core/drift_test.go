@@ -0,0 +1,84 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestSopsManager_CheckDrift(t *testing.T) {
+	t.Parallel()
+
+	// Given: carol left production, but prod.env is still encrypted for her
+	service, output, tempDir := setupScopeTest(t)
+	writeTestFile(t, tempDir, "dev.env", sopsDotenvContent(testRecipientA, testRecipientB))
+	prodFile := writeTestFile(t, tempDir, "prod.env", sopsDotenvContent(testRecipientA, testRecipientC))
+
+	// When: checking for drift
+	err := service.CheckDrift()
+
+	// Then: it fails with the drift outcome and names the removed member
+	if !errors.Is(err, ErrDrift) || ExitCode(err) != ExitDrift {
+		t.Fatalf("expected drift outcome, got %v", err)
+	}
+	if !containsString(output.String(), "1 of 2 managed files") || !containsString(output.String(), "still readable by carol") {
+		t.Errorf("expected drift details, got: %s", output.String())
+	}
+
+	// When: checking with JSON output
+	var jsonOutput bytes.Buffer
+	service.WithJSONOutput(true).WithReporter(NewWriterReporter(&jsonOutput))
+	err = service.CheckDrift()
+
+	// Then: the drifted file is listed with the members it should lose
+	if !errors.Is(err, ErrDrift) {
+		t.Fatalf("expected drift outcome, got %v", err)
+	}
+	var doc struct {
+		Result  DriftResult `json:"result"`
+		Command string      `json:"command"`
+	}
+	requireNoError(t, json.Unmarshal(jsonOutput.Bytes(), &doc), "output should be valid JSON")
+	if doc.Command != "check-drift" {
+		t.Errorf("expected command check-drift, got %q", doc.Command)
+	}
+	if doc.Result.Checked != 2 || len(doc.Result.Files) != 1 {
+		t.Fatalf("expected one of two files drifted, got %+v", doc.Result)
+	}
+	if file := doc.Result.Files[0]; file.File != prodFile || !slices.Equal(file.Removed, []string{"carol"}) {
+		t.Errorf("expected prod.env losing carol, got %+v", file)
+	}
+}
+
+func TestPlan_Drift(t *testing.T) {
+	t.Parallel()
+
+	// Given: a plaintext file, a file missing a new member and an up-to-date file
+	tempDir := t.TempDir()
+	writeTestFile(t, tempDir, "dev.env", sopsDotenvContent(testRecipientA))
+	writeTestFile(t, tempDir, "prod.env", sopsDotenvContent(testRecipientA))
+	writeTestFile(t, tempDir, "qa.env", "KEY=value\n")
+
+	manifest := createScopedManifest(tempDir)
+	manifest.Scopes = append(manifest.Scopes, Scope{Name: "qa", Patterns: []string{filepath.Join(tempDir, "qa.env")}, Members: []string{"carol"}})
+
+	plan, err := NewPlanner("sops").ComputePlan(manifest)
+	requireNoError(t, err, "computing plan should succeed")
+
+	// When: listing drift
+	drifted := plan.Drift()
+
+	// Then: the plaintext file and the file missing bob are reported
+	if len(drifted) != 2 {
+		t.Fatalf("expected two drifted files, got %+v", drifted)
+	}
+	if drifted[0].describe() != "not encrypted for bob" {
+		t.Errorf("unexpected description for dev.env: %q", drifted[0].describe())
+	}
+	if !drifted[1].Plaintext || drifted[1].describe() != "not encrypted" {
+		t.Errorf("expected qa.env to be plaintext, got %+v", drifted[1])
+	}
+}
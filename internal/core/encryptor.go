@@ -18,6 +18,7 @@ func ensureBinaryAvailable(binaryPath, installMessage string) error {
 
 // Encryptor handles SOPS file encryption operations
 type Encryptor struct {
+	reporter Reporter
 	sopsPath string
 }
 
@@ -28,10 +29,17 @@ func NewEncryptor(sopsPath string) *Encryptor {
 	}
 	cleanPath := filepath.Clean(sopsPath)
 	return &Encryptor{
+		reporter: NewConsoleReporter(ReporterOptions{Level: LevelNormal}),
 		sopsPath: cleanPath,
 	}
 }
 
+// WithReporter sets where encryption results and warnings go
+func (e *Encryptor) WithReporter(reporter Reporter) *Encryptor {
+	e.reporter = reporter
+	return e
+}
+
 // EncryptFile encrypts a file using SOPS with the provided age keys
 func (e *Encryptor) EncryptFile(filePath string, ageKeys []string, inPlace bool, regex string) error {
 	if err := e.validateEncryptionInputs(filePath); err != nil {
@@ -49,7 +57,7 @@ func (e *Encryptor) EncryptFile(filePath string, ageKeys []string, inPlace bool,
 
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("sops encryption failed: %s", string(output))
+		return NewCryptoError("encrypt", filePath, fmt.Errorf("sops: %s", strings.TrimSpace(string(output))))
 	}
 
 	e.displayEncryptionResult(filePath, inPlace, regex, output)
@@ -62,7 +70,7 @@ func (e *Encryptor) validateEncryptionInputs(filePath string) error {
 	}
 
 	if err := ensureBinaryAvailable(e.sopsPath, "Please install SOPS"); err != nil {
-		return err
+		return NewCryptoError("encrypt", filePath, err)
 	}
 
 	return nil
@@ -76,7 +84,7 @@ func (e *Encryptor) checkSOPSConflicts() error {
 	}
 
 	if sopsInfo.ShouldWarn() {
-		fmt.Printf("%s\n\n", sopsInfo.GetWarningMessage())
+		e.reporter.Warnf("%s\n", sopsInfo.GetWarningMessage())
 	}
 
 	return nil
@@ -93,6 +101,7 @@ func (e *Encryptor) buildEncryptCommand(filePath string, ageKeys []string, inPla
 
 	ageRecipients := strings.Join(ageKeys, ",")
 	cmd.Env = append(os.Environ(), fmt.Sprintf("SOPS_AGE_RECIPIENTS=%s", ageRecipients))
+	e.reporter.Debugf("Running SOPS_AGE_RECIPIENTS=%s %s", ageRecipients, strings.Join(cmd.Args, " "))
 
 	return cmd, nil
 }
@@ -112,17 +121,18 @@ func (e *Encryptor) buildSOPSArgs(filePath string, inPlace bool, regex string) [
 func (e *Encryptor) displayEncryptionResult(filePath string, inPlace bool, regex string, output []byte) { //nolint:revive // inPlace is a legitimate CLI flag parameter
 	if inPlace {
 		if regex != "" {
-			fmt.Printf("🔒 Encrypted %s (partial: %s)\n", filePath, regex)
+			e.reporter.Infof("🔒 Encrypted %s (partial: %s)", filePath, regex)
 		} else {
-			fmt.Printf("🔒 Encrypted %s (full file)\n", filePath)
+			e.reporter.Infof("🔒 Encrypted %s (full file)", filePath)
 		}
 	} else {
-		fmt.Print(string(output))
+		_, _ = e.reporter.Out().Write(output)
 	}
 }
 
 // Decryptor handles SOPS decryption operations
 type Decryptor struct {
+	reporter Reporter
 	sopsPath string
 }
 
@@ -135,10 +145,17 @@ func NewDecryptor(sopsPath string) *Decryptor {
 	// Clean the path to prevent injection
 	cleanPath := filepath.Clean(sopsPath)
 	return &Decryptor{
+		reporter: NewConsoleReporter(ReporterOptions{Level: LevelNormal}),
 		sopsPath: cleanPath,
 	}
 }
 
+// WithReporter sets where decrypted content and status messages go
+func (d *Decryptor) WithReporter(reporter Reporter) *Decryptor {
+	d.reporter = reporter
+	return d
+}
+
 // DecryptFile decrypts a SOPS-encrypted file
 func (d *Decryptor) DecryptFile(filePath, keyPath string, inPlace bool) error { //nolint:revive // inPlace is a legitimate CLI flag parameter
 	// Check if file exists
@@ -148,12 +165,12 @@ func (d *Decryptor) DecryptFile(filePath, keyPath string, inPlace bool) error {
 
 	// Check if key file exists
 	if _, err := os.Stat(keyPath); err != nil {
-		return fmt.Errorf("age key file %s does not exist: %w", keyPath, err)
+		return NewKeyError("find", keyPath, fmt.Errorf("age key file %s does not exist: %w", keyPath, err))
 	}
 
 	// Check if SOPS is available
 	if err := ensureBinaryAvailable(d.sopsPath, "Please install SOPS"); err != nil {
-		return err
+		return NewCryptoError("decrypt", filePath, err)
 	}
 
 	// Build SOPS command
@@ -171,17 +188,18 @@ func (d *Decryptor) DecryptFile(filePath, keyPath string, inPlace bool) error {
 
 	// Set age identity file as environment variable
 	cmd.Env = append(os.Environ(), fmt.Sprintf("SOPS_AGE_KEY_FILE=%s", keyPath))
+	d.reporter.Debugf("Running SOPS_AGE_KEY_FILE=%s %s", keyPath, strings.Join(cmd.Args, " "))
 
 	// Execute command
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("sops decryption failed: %s", string(output))
+		return NewCryptoError("decrypt", filePath, fmt.Errorf("sops: %s", strings.TrimSpace(string(output))))
 	}
 
 	if inPlace {
-		fmt.Printf("🔓 Decrypted %s\n", filePath)
+		d.reporter.Infof("🔓 Decrypted %s", filePath)
 	} else {
-		fmt.Print(string(output))
+		_, _ = d.reporter.Out().Write(output)
 	}
 
 	return nil
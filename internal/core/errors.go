@@ -1,6 +1,9 @@
 package core
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
 
 // SopsError represents different categories of SOPS-related errors
 type SopsError interface {
@@ -65,6 +68,99 @@ func (e *CryptoError) Unwrap() error {
 	return e.Cause
 }
 
+// GitError represents errors related to the git working tree
+type GitError struct {
+	Cause     error
+	Operation string // "status", "check-clean"
+}
+
+func (e *GitError) Error() string {
+	return fmt.Sprintf("git %s failed: %v", e.Operation, e.Cause)
+}
+
+func (e *GitError) Category() string {
+	return "git"
+}
+
+func (e *GitError) Unwrap() error {
+	return e.Cause
+}
+
+// outcomeError reports a result that is not a failure but must still be
+// visible in the exit code, such as a cancelled prompt
+type outcomeError struct {
+	category string
+	message  string
+}
+
+func (e *outcomeError) Error() string {
+	return e.message
+}
+
+func (e *outcomeError) Category() string {
+	return e.category
+}
+
+var (
+	// ErrCancelled is returned when the user declines a confirmation prompt
+	ErrCancelled SopsError = &outcomeError{category: "cancelled", message: "cancelled by user"}
+	// ErrPlanHasChanges is returned by 'sistry plan --detailed-exitcode' when the plan would change files
+	ErrPlanHasChanges SopsError = &outcomeError{category: "changes", message: "plan has changes"}
+	// ErrDrift is returned by 'sistry check --drift' when encrypted files do not match the manifest
+	ErrDrift SopsError = &outcomeError{category: "drift", message: "encrypted files do not match the manifest"}
+)
+
+// IsOutcome reports whether err only signals an outcome (cancelled, plan has
+// changes, drift) that has already been shown and should not be printed as an error
+func IsOutcome(err error) bool {
+	var outcome *outcomeError
+	return errors.As(err, &outcome)
+}
+
+// Exit codes returned by the sistry binary. Wrapper scripts may rely on these.
+const (
+	ExitOK             = 0 // Success; for 'plan --detailed-exitcode', no changes
+	ExitError          = 1 // Any error without a more specific code, including usage errors
+	ExitPlanHasChanges = 2 // 'plan --detailed-exitcode' found changes
+	ExitManifest       = 3 // sopsistry.yaml missing, unreadable or invalid
+	ExitKey            = 4 // Age key missing, expired or could not be generated
+	ExitCrypto         = 5 // SOPS encryption or decryption failed
+	ExitGitDirty       = 6 // Git working tree not clean or not a repository
+	ExitCancelled      = 7 // User declined a confirmation prompt
+	ExitDrift          = 8 // 'check --drift' found files not encrypted for exactly their scope
+)
+
+// ExitCode maps err to the exit code of its SopsError category
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	var sopsErr SopsError
+	if !errors.As(err, &sopsErr) {
+		return ExitError
+	}
+
+	switch sopsErr.Category() {
+	case "changes":
+		return ExitPlanHasChanges
+	case "manifest":
+		return ExitManifest
+	case "key":
+		return ExitKey
+	case "crypto":
+		return ExitCrypto
+	case "git":
+		return ExitGitDirty
+	case "cancelled":
+		return ExitCancelled
+	case "drift":
+		return ExitDrift
+	default:
+		return ExitError
+	}
+}
+
 // Helper functions for creating typed errors
 func NewManifestError(op, path string, cause error) *ManifestError {
 	return &ManifestError{Operation: op, Path: path, Cause: cause}
@@ -77,3 +173,7 @@ func NewKeyError(op, keyID string, cause error) *KeyError {
 func NewCryptoError(op, filePath string, cause error) *CryptoError {
 	return &CryptoError{Operation: op, FilePath: filePath, Cause: cause}
 }
+
+func NewGitError(op string, cause error) *GitError {
+	return &GitError{Operation: op, Cause: cause}
+}
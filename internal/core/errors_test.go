@@ -0,0 +1,56 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	t.Parallel()
+
+	cause := errors.New("cause")
+	tests := []struct {
+		err  error
+		name string
+		want int
+	}{
+		{name: "success", err: nil, want: ExitOK},
+		{name: "untyped error", err: cause, want: ExitError},
+		{name: "plan has changes", err: ErrPlanHasChanges, want: ExitPlanHasChanges},
+		{name: "manifest error", err: NewManifestError("load", "sopsistry.yaml", cause), want: ExitManifest},
+		{name: "key error", err: NewKeyError("rotate", "alice", cause), want: ExitKey},
+		{name: "crypto error", err: NewCryptoError("encrypt", "dev.env", cause), want: ExitCrypto},
+		{name: "git error", err: NewGitError("check-clean", cause), want: ExitGitDirty},
+		{name: "cancelled", err: ErrCancelled, want: ExitCancelled},
+		{name: "drift", err: ErrDrift, want: ExitDrift},
+		{name: "wrapped typed error", err: fmt.Errorf(FailedToLoadManifestMsg, NewManifestError("parse", "sopsistry.yaml", cause)), want: ExitManifest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("expected exit code %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestLoadManifest_MissingFileIsManifestError(t *testing.T) {
+	t.Parallel()
+
+	// Given: no manifest on disk
+	// When: loading it
+	_, err := LoadManifest(t.TempDir() + "/sopsistry.yaml")
+
+	// Then: the error carries the manifest category
+	var manifestErr *ManifestError
+	if !errors.As(err, &manifestErr) {
+		t.Fatalf("expected ManifestError, got %T: %v", err, err)
+	}
+	if manifestErr.Operation != "load" {
+		t.Errorf("expected load operation, got %s", manifestErr.Operation)
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 // Executor handles the actual execution of planned SOPS operations
 type Executor struct {
+	reporter Reporter
 	sopsPath string
 }
 
@@ -20,24 +21,43 @@ func NewExecutor(sopsPath string) *Executor {
 	}
 	cleanPath := filepath.Clean(sopsPath)
 	return &Executor{
+		reporter: NewConsoleReporter(ReporterOptions{Level: LevelNormal}),
 		sopsPath: cleanPath,
 	}
 }
 
-// Execute runs all actions in the plan atomically
-func (e *Executor) Execute(plan *Plan) error {
-	if len(plan.Actions) == 0 {
-		fmt.Println("No actions to execute")
-		return nil
+// WithReporter sets where progress messages go
+func (e *Executor) WithReporter(reporter Reporter) *Executor {
+	e.reporter = reporter
+	return e
+}
+
+// Execute runs all actions in the plan atomically. The returned result
+// reports the outcome of every file-changing action, also on failure.
+func (e *Executor) Execute(plan *Plan) (*ApplyResult, error) {
+	result := newApplyResult(plan)
+	if !plan.HasChanges() {
+		e.reporter.Infof("No actions to execute")
+		return result, nil
 	}
 
 	backupDir, err := e.setupBackupDirectory()
 	if err != nil {
-		return err
+		return result, err
 	}
 	defer func() { _ = os.RemoveAll(backupDir) }() //nolint:errcheck // Cleanup operation, error not critical
 
-	return e.executeActionsWithRollback(plan, backupDir)
+	return result, e.executeActionsWithRollback(plan, backupDir, result)
+}
+
+// newApplyResult lists every file-changing action as not yet run
+func newApplyResult(plan *Plan) *ApplyResult {
+	changes := Filter(plan.Actions, func(a Action) bool { return a.Type.ChangesFile() })
+	return &ApplyResult{
+		Files: MapSlice(changes, func(a Action) FileResult {
+			return FileResult{File: a.File, Action: a.Type, Status: FileNotRun}
+		}),
+	}
 }
 
 func (e *Executor) setupBackupDirectory() (string, error) {
@@ -48,11 +68,11 @@ func (e *Executor) setupBackupDirectory() (string, error) {
 	return backupDir, nil
 }
 
-func (e *Executor) executeActionsWithRollback(plan *Plan, backupDir string) error {
-	executedActions := 0
+func (e *Executor) executeActionsWithRollback(plan *Plan, backupDir string, result *ApplyResult) error {
+	progress := e.reporter.StartProgress(len(result.Files))
 
 	for i, action := range plan.Actions {
-		if action.Type == ActionSkip {
+		if !action.Type.ChangesFile() {
 			continue
 		}
 
@@ -60,15 +80,19 @@ func (e *Executor) executeActionsWithRollback(plan *Plan, backupDir string) erro
 			return err
 		}
 
+		fileResult := &result.Files[result.Applied]
 		if err := e.executeAction(&action); err != nil {
-			return e.handleExecutionError(&action, err, plan.Actions[:executedActions+1], backupDir)
+			fileResult.Status = FileFailed
+			fileResult.Error = err.Error()
+			return e.handleExecutionError(&action, err, plan.Actions[:i+1], backupDir, result)
 		}
 
-		executedActions++
-		fmt.Printf("✓ %s %s\n", action.Type, action.File)
+		fileResult.Status = FileApplied
+		result.Applied++
+		progress.Step("%s %s", action.Type, action.File)
 	}
 
-	fmt.Printf("\nSuccessfully applied %d changes\n", executedActions)
+	e.reporter.Infof("\nSuccessfully applied %d changes", result.Applied)
 	return nil
 }
 
@@ -78,18 +102,24 @@ func (e *Executor) backupFileIfExists(filePath, backupDir string, index int) err
 		if err := e.copyFile(filePath, backupPath); err != nil {
 			return fmt.Errorf("failed to backup %s: %w", filePath, err)
 		}
+		e.reporter.Verbosef("Backed up %s to %s", filePath, backupPath)
 	}
 	return nil
 }
 
-func (e *Executor) handleExecutionError(action *Action, actionErr error, executedActions []Action, backupDir string) error {
-	fmt.Printf("Error executing action for %s: %v\n", action.File, actionErr)
-	fmt.Println("Rolling back changes...")
+func (e *Executor) handleExecutionError(action *Action, actionErr error, executedActions []Action, backupDir string, result *ApplyResult) error {
+	e.reporter.Warnf("Error executing action for %s: %v", action.File, actionErr)
+	e.reporter.Infof("Rolling back changes...")
 
 	if rollbackErr := e.rollback(executedActions, backupDir); rollbackErr != nil {
 		return fmt.Errorf("execution failed and rollback failed: %w (original error: %w)", rollbackErr, actionErr)
 	}
 
+	for i := range result.Applied {
+		result.Files[i].Status = FileRolledBack
+	}
+	result.Applied = 0
+
 	return fmt.Errorf("execution failed: %w", actionErr)
 }
 
@@ -100,8 +130,8 @@ func (e *Executor) executeAction(action *Action) error {
 		return e.encryptFile(action.File, action.Recipients)
 	case ActionReencrypt:
 		return e.reencryptFile(action.File, action.Recipients)
-	case ActionSkip:
-		return nil // Skip action, nothing to do
+	case ActionSkip, ActionNoop:
+		return nil // Nothing to do
 	default:
 		return fmt.Errorf("unknown action type: %s", action.Type)
 	}
@@ -116,10 +146,11 @@ func (e *Executor) encryptFile(file string, recipients []string) error {
 	// Use environment variable to specify age recipients
 	cmd := exec.Command(e.sopsPath, "-e", "--in-place", file) //nolint:gosec // sopsPath validated by isValidSOPSPath()
 	cmd.Env = append(os.Environ(), fmt.Sprintf("SOPS_AGE_RECIPIENTS=%s", strings.Join(recipients, ",")))
+	e.reporter.Debugf("Running SOPS_AGE_RECIPIENTS=%s %s", strings.Join(recipients, ","), strings.Join(cmd.Args, " "))
 
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("sops encrypt failed: %s", string(output))
+		return NewCryptoError("encrypt", file, fmt.Errorf("sops: %s", strings.TrimSpace(string(output))))
 	}
 
 	return nil
@@ -134,10 +165,11 @@ func (e *Executor) reencryptFile(file string, recipients []string) error {
 	// Use SOPS native rotate command for atomic key rotation
 	args := []string{"--rotate", "--age", strings.Join(recipients, ","), file}
 	cmd := exec.Command(e.sopsPath, args...) //nolint:gosec // sopsPath validated by isValidSOPSPath()
+	e.reporter.Debugf("Running %s", strings.Join(cmd.Args, " "))
 
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("sops rotate failed for %s: %s", file, string(output))
+		return NewCryptoError("reencrypt", file, fmt.Errorf("sops: %s", strings.TrimSpace(string(output))))
 	}
 
 	return nil
@@ -146,7 +178,7 @@ func (e *Executor) reencryptFile(file string, recipients []string) error {
 // rollback restores files from backup
 func (e *Executor) rollback(actions []Action, backupDir string) error {
 	for i, action := range actions {
-		if action.Type == ActionSkip {
+		if !action.Type.ChangesFile() {
 			continue
 		}
 
@@ -155,7 +187,7 @@ func (e *Executor) rollback(actions []Action, backupDir string) error {
 			if err := e.copyFile(backupPath, action.File); err != nil {
 				return fmt.Errorf("failed to restore %s: %w", action.File, err)
 			}
-			fmt.Printf("↺ Restored %s\n", action.File)
+			e.reporter.Infof("↺ Restored %s", action.File)
 		}
 	}
 	return nil
@@ -13,17 +13,17 @@ import (
 func (s *SopsManager) checkGitClean() error {
 	cmd := exec.Command("git", "rev-parse", "--git-dir")
 	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("not in a git repository")
+		return NewGitError("check-clean", fmt.Errorf("not in a git repository"))
 	}
 
 	cmd = exec.Command("git", "status", "--porcelain")
 	output, err := cmd.Output()
 	if err != nil {
-		return fmt.Errorf("failed to check git status: %w", err)
+		return NewGitError("status", err)
 	}
 
 	if strings.TrimSpace(string(output)) != "" {
-		return fmt.Errorf("git working tree is not clean. Commit or stash changes first, or use --force")
+		return NewGitError("check-clean", fmt.Errorf("working tree is not clean. Commit or stash changes first, or use --force"))
 	}
 
 	return nil
@@ -78,7 +78,7 @@ func (s *SopsManager) addSecretsToGitignore(gitignorePath string, lines []string
 		return fmt.Errorf("failed to update .gitignore: %w", err)
 	}
 
-	fmt.Printf("Added .secrets to .gitignore\n")
+	s.reporter.Infof("Added .secrets to .gitignore")
 	return nil
 }
 
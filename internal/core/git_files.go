@@ -0,0 +1,228 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// gitPathBatchSize bounds the paths passed to one git command, keeping its
+// argument list well below the system limit on large trees
+const gitPathBatchSize = 500
+
+// FileDiscovery selects how scope patterns are resolved to files
+type FileDiscovery string
+
+// File discovery modes, set with settings.file_discovery in the manifest
+const (
+	DiscoveryAuto       FileDiscovery = "auto"       // Consult git for files inside a work tree; default
+	DiscoveryGit        FileDiscovery = "git"        // Like auto, but files outside a work tree are an error
+	DiscoveryFilesystem FileDiscovery = "filesystem" // Every file matching a pattern, ignored or not
+)
+
+// ParseFileDiscovery validates a settings.file_discovery value; empty means auto
+func ParseFileDiscovery(value string) (FileDiscovery, error) {
+	switch mode := FileDiscovery(value); mode {
+	case "":
+		return DiscoveryAuto, nil
+	case DiscoveryAuto, DiscoveryGit, DiscoveryFilesystem:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown file discovery %q: must be auto, git or filesystem", value)
+	}
+}
+
+// gitDiscovery filters pattern matches through git so that files ignored by
+// .gitignore are never managed, and remembers matches that are ignored or not
+// yet tracked
+type gitDiscovery struct {
+	toplevels map[string]string   // Directory -> work tree root, "" outside a work tree
+	listed    map[string][]string // Pattern base directory -> candidates, listed once per plan
+	untracked *Set[string]
+	ignored   *Set[string]
+	mode      FileDiscovery
+}
+
+func newGitDiscovery(mode FileDiscovery) *gitDiscovery {
+	return &gitDiscovery{
+		mode:      mode,
+		toplevels: make(map[string]string),
+		listed:    make(map[string][]string),
+		untracked: NewSet[string](),
+		ignored:   NewSet[string](),
+	}
+}
+
+// filter drops files git ignores, recording them and untracked ones
+func (g *gitDiscovery) filter(files []string) ([]string, error) {
+	if g.mode == DiscoveryFilesystem || len(files) == 0 {
+		return files, nil
+	}
+
+	byWorkTree := make(map[string][]string)
+	for _, file := range files {
+		toplevel, err := g.workTree(filepath.Dir(file))
+		if err != nil {
+			return nil, err
+		}
+		if toplevel == "" && g.mode == DiscoveryGit {
+			return nil, NewGitError("ls-files", fmt.Errorf("%s is not inside a git work tree", file))
+		}
+		if toplevel != "" {
+			byWorkTree[toplevel] = append(byWorkTree[toplevel], file)
+		}
+	}
+
+	ignored, untracked := NewSet[string](), NewSet[string]()
+	for toplevel, candidates := range byWorkTree {
+		treeIgnored, err := g.listOthers(toplevel, candidates, "--ignored")
+		if err != nil {
+			return nil, err
+		}
+		treeUntracked, err := g.listOthers(toplevel, candidates)
+		if err != nil {
+			return nil, err
+		}
+		ignored, untracked = ignored.Union(treeIgnored), untracked.Union(treeUntracked)
+	}
+
+	var kept []string
+	for _, file := range files {
+		resolved := resolvedPath(file)
+		if ignored.Contains(resolved) {
+			g.ignored.Add(file)
+			continue
+		}
+		if untracked.Contains(resolved) {
+			g.untracked.Add(file)
+		}
+		kept = append(kept, file)
+	}
+	return kept, nil
+}
+
+// isUntracked reports whether file matched a pattern but is not tracked by git
+func (g *gitDiscovery) isUntracked(file string) bool {
+	return g.untracked.Contains(file)
+}
+
+// ignoredFiles returns the files filter dropped because git ignores them
+func (g *gitDiscovery) ignoredFiles() []string {
+	files := g.ignored.ToSlice()
+	slices.Sort(files)
+	return files
+}
+
+// listFiles returns the files below root that git tracks or would track, so
+// ignored directories such as node_modules are never descended into. listed is
+// false in filesystem mode and outside a work tree, where the caller must walk
+// root itself.
+func (g *gitDiscovery) listFiles(root string) (files []string, listed bool, err error) {
+	if g.mode == DiscoveryFilesystem {
+		return nil, false, nil
+	}
+	toplevel, err := g.workTree(root)
+	if err != nil || toplevel == "" {
+		return nil, false, err
+	}
+
+	files, err = lsFiles(root, "--cached", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, false, err
+	}
+	return files, true, nil
+}
+
+// candidates returns the files below root that scope patterns are matched
+// against: those listFiles returns plus the ignored ones, which filter then
+// reports. Each root is listed once per plan, however many patterns and
+// scopes share it. listed is false where listFiles' is.
+func (g *gitDiscovery) candidates(root string) (files []string, listed bool, err error) {
+	if files, ok := g.listed[root]; ok {
+		return files, true, nil
+	}
+
+	if files, listed, err = g.listFiles(root); err != nil || !listed {
+		return nil, listed, err
+	}
+	ignored, err := lsFiles(root, "--others", "--ignored", "--exclude-standard")
+	if err != nil {
+		return nil, false, err
+	}
+	files = append(files, ignored...)
+	g.listed[root] = files
+	return files, true, nil
+}
+
+// lsFiles runs git ls-files in root and returns the files it lists, joined to root
+func lsFiles(root string, args ...string) ([]string, error) {
+	args = append([]string{"-C", root, "ls-files", "-z"}, args...)
+	output, err := exec.Command("git", args...).Output() //nolint:gosec // Arguments are fixed flags and a pattern's base directory
+	if err != nil {
+		return nil, NewGitError("ls-files", fmt.Errorf("git ls-files failed in %s: %w", root, err))
+	}
+
+	var files []string
+	for _, name := range bytes.Split(output, []byte{0}) {
+		if len(name) > 0 {
+			files = append(files, filepath.Join(root, string(name)))
+		}
+	}
+	return files, nil
+}
+
+// workTree returns the root of the git work tree containing dir, or "" if there is none
+func (g *gitDiscovery) workTree(dir string) (string, error) {
+	if toplevel, ok := g.toplevels[dir]; ok {
+		return toplevel, nil
+	}
+
+	var toplevel string
+	output, err := exec.Command("git", "-C", dir, "rev-parse", "--show-toplevel").Output() //nolint:gosec // dir comes from scope patterns in the manifest
+	if err == nil {
+		toplevel = strings.TrimSpace(string(output))
+	} else if _, lookErr := exec.LookPath("git"); lookErr != nil && g.mode == DiscoveryGit {
+		return "", NewGitError("ls-files", fmt.Errorf("file_discovery is git, but git is not installed"))
+	}
+
+	g.toplevels[dir] = toplevel
+	return toplevel, nil
+}
+
+// listOthers returns the absolute paths of files among candidates that git
+// does not track, excluding ignored files unless "--ignored" is passed.
+// Candidates are passed to git in batches of gitPathBatchSize.
+func (g *gitDiscovery) listOthers(toplevel string, candidates []string, extraArgs ...string) (*Set[string], error) {
+	files := NewSet[string]()
+	for batch := range slices.Chunk(candidates, gitPathBatchSize) {
+		args := append([]string{"-C", toplevel, "ls-files", "-z", "--others", "--exclude-standard"}, extraArgs...)
+		args = append(args, "--")
+		args = append(args, MapSlice(batch, resolvedPath)...)
+
+		output, err := exec.Command("git", args...).Output() //nolint:gosec // Arguments are fixed flags and file paths
+		if err != nil {
+			return nil, NewGitError("ls-files", fmt.Errorf("git ls-files failed in %s: %w", toplevel, err))
+		}
+		for _, name := range bytes.Split(output, []byte{0}) {
+			if len(name) > 0 {
+				files.Add(filepath.Join(toplevel, string(name)))
+			}
+		}
+	}
+	return files, nil
+}
+
+// resolvedPath returns the absolute, symlink-free form of file, matching the paths git reports
+func resolvedPath(file string) string {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return file
+	}
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		return resolved
+	}
+	return abs
+}
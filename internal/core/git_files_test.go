@@ -0,0 +1,155 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func setupGitRepo(t *testing.T) string {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	dir := t.TempDir()
+	writeTestFile(t, dir, ".gitignore", "build/\n")
+	writeTestFile(t, dir, "dev.env", "KEY=value\n")
+	writeTestFile(t, dir, "prod.env.bak", "KEY=value\n")
+	writeTestFile(t, dir, "build/out.env", "KEY=value\n")
+
+	for _, args := range [][]string{{"init", "-q"}, {"add", ".gitignore", "dev.env"}} {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+	return dir
+}
+
+func TestPlanner_GitAwareDiscovery(t *testing.T) {
+	t.Parallel()
+
+	// Given: a repository with a tracked file, an untracked copy and ignored build output
+	dir := setupGitRepo(t)
+	manifest := &Manifest{
+		Members: []Member{{ID: "alice", AgeKey: testRecipientA}},
+		Scopes:  []Scope{{Name: "all", Patterns: []string{filepath.Join(dir, "**/*.env*")}, Members: []string{"alice"}}},
+	}
+
+	// When: planning with the default discovery
+	plan, err := NewPlanner("sops").ComputePlan(manifest)
+
+	// Then: ignored files are left out and the untracked copy is flagged
+	requireNoError(t, err, "computing plan should succeed")
+	if len(plan.Actions) != 2 {
+		t.Fatalf("expected dev.env and prod.env.bak, got %+v", plan.Actions)
+	}
+	if findActionForFile(t, plan, filepath.Join(dir, "dev.env")).Untracked {
+		t.Error("tracked dev.env should not be flagged")
+	}
+	if !findActionForFile(t, plan, filepath.Join(dir, "prod.env.bak")).Untracked {
+		t.Error("untracked prod.env.bak should be flagged")
+	}
+
+	if len(plan.Ignored) != 1 || plan.Ignored[0] != filepath.Join(dir, "build/out.env") {
+		t.Errorf("expected build/out.env to be reported as ignored, got %v", plan.Ignored)
+	}
+
+	var output bytes.Buffer
+	plan.Display(NewConsoleReporter(ReporterOptions{Out: &output, Err: &output, Level: LevelVerbose, NoColor: true}))
+	if !containsString(output.String(), "1 matched files are not tracked by git") {
+		t.Errorf("expected untracked warning, got: %s", output.String())
+	}
+	if !containsString(output.String(), "1 matched files are ignored by git and not managed") {
+		t.Errorf("expected ignored files in verbose output, got: %s", output.String())
+	}
+
+	// When: discovery is set to the filesystem
+	manifest.Settings.FileDiscovery = string(DiscoveryFilesystem)
+	plan, err = NewPlanner("sops").ComputePlan(manifest)
+
+	// Then: ignored files are planned as well
+	requireNoError(t, err, "computing plan should succeed")
+	if len(plan.Actions) != 3 {
+		t.Errorf("expected all three files, got %+v", plan.Actions)
+	}
+}
+
+func TestPlanner_MatchesScopesAgainstOneGitListing(t *testing.T) {
+	t.Parallel()
+
+	// Given: two scopes searching the same repository, which has ignored build output
+	dir := setupGitRepo(t)
+	manifest := &Manifest{
+		Members: []Member{{ID: "alice", AgeKey: testRecipientA}},
+		Scopes: []Scope{
+			{Name: "all", Patterns: []string{filepath.Join(dir, "**/*.env")}, Members: []string{"alice"}},
+			{Name: "dev", Patterns: []string{filepath.Join(dir, "**/dev.env")}, Members: []string{"alice"}},
+		},
+	}
+	planner := NewPlanner("sops")
+
+	// When: matching the scopes and resolving a file's scopes
+	files, _, err := planner.scopeMatches(manifest)
+	requireNoError(t, err, "matching scopes should succeed")
+	matched, err := planner.matchingScopes(manifest, filepath.Join(dir, "dev.env"))
+	requireNoError(t, err, "resolving scopes should succeed")
+
+	// Then: the repository is listed once, ignored files are reported rather than matched, and both scopes match dev.env
+	if len(planner.git.listed) != 1 {
+		t.Errorf("expected one git listing, got %v", planner.git.listed)
+	}
+	if len(files) != 1 || files[0] != filepath.Join(dir, "dev.env") {
+		t.Errorf("expected only dev.env, got %v", files)
+	}
+	if ignored := planner.git.ignoredFiles(); len(ignored) != 1 || ignored[0] != filepath.Join(dir, "build/out.env") {
+		t.Errorf("expected build/out.env to be reported as ignored, got %v", ignored)
+	}
+	if names := scopeNames(matched); len(names) != 2 || names[0] != "all" || names[1] != "dev" {
+		t.Errorf("expected dev.env to match all and dev, got %v", names)
+	}
+}
+
+func TestGitDiscovery_FiltersManyFilesInBatches(t *testing.T) {
+	t.Parallel()
+
+	// Given: more untracked candidates than fit in one git command
+	dir := setupGitRepo(t)
+	var files []string
+	for i := range gitPathBatchSize + 10 {
+		files = append(files, writeTestFile(t, dir, fmt.Sprintf("many/%d.env", i), "KEY=value\n"))
+	}
+
+	// When: filtering them
+	git := newGitDiscovery(DiscoveryAuto)
+	kept, err := git.filter(files)
+
+	// Then: every batch is consulted
+	requireNoError(t, err, "filtering should succeed")
+	if len(kept) != len(files) || !git.isUntracked(files[len(files)-1]) {
+		t.Errorf("expected all %d files kept and untracked, got %d", len(files), len(kept))
+	}
+}
+
+func TestPlanner_GitDiscoveryOutsideWorkTree(t *testing.T) {
+	t.Parallel()
+
+	// Given: files outside any git work tree, with discovery forced to git
+	tempDir := t.TempDir()
+	writeTestFile(t, tempDir, "dev.env", "KEY=value\n")
+	manifest := createScopedManifest(tempDir)
+	manifest.Settings.FileDiscovery = string(DiscoveryGit)
+
+	// When: planning
+	_, err := NewPlanner("sops").ComputePlan(manifest)
+
+	// Then: it fails with a git error
+	requireError(t, err, "git discovery outside a work tree should fail")
+	if ExitCode(err) != ExitGitDirty {
+		t.Errorf("expected git exit code, got %d: %v", ExitCode(err), err)
+	}
+}
@@ -0,0 +1,234 @@
+package core
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// accessWarningDays is how long before a time-limited grant ends check warns about it
+const accessWarningDays = 7
+
+// scopeGrant is a scope member entry written as {member: bob, until: 2026-11-01}
+type scopeGrant struct {
+	Member string `yaml:"member"`
+	Until  string `yaml:"until"`
+}
+
+// parseGrantDate parses the until date of a time-limited grant
+func parseGrantDate(value string) (time.Time, error) {
+	date, err := time.Parse(DateFormat, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid until date %q: want YYYY-MM-DD", value)
+	}
+	return date, nil
+}
+
+// grantExpired reports whether a grant has ended at now. Access lasts through
+// the until date, in UTC.
+func grantExpired(until, now time.Time) bool {
+	return !now.Before(until.AddDate(0, 0, 1))
+}
+
+// UnmarshalYAML decodes a scope, accepting time-limited grants among its members
+func (s *Scope) UnmarshalYAML(node *yaml.Node) error {
+	type plain Scope
+	decoded := *node
+	until := make(map[string]time.Time)
+
+	if i := mappingKeyIndex(node, "members"); i >= 0 {
+		members := *resolveAlias(node.Content[i+1])
+		members.Content = slices.Clone(members.Content)
+		for j, item := range members.Content {
+			if item.Kind != yaml.MappingNode {
+				continue
+			}
+			var grant scopeGrant
+			if err := item.Decode(&grant); err != nil {
+				return err
+			}
+			if grant.Until != "" {
+				date, err := parseGrantDate(grant.Until)
+				if err != nil {
+					return fmt.Errorf("line %d: scope member %s: %w", item.Line, grant.Member, err)
+				}
+				until[grant.Member] = date
+			}
+			members.Content[j] = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: grant.Member, Line: item.Line, Column: item.Column}
+		}
+		decoded.Content = slices.Clone(node.Content)
+		decoded.Content[i+1] = &members
+	}
+
+	if err := decoded.Decode((*plain)(s)); err != nil {
+		return err
+	}
+	if len(until) > 0 {
+		s.Until = until
+	}
+	return nil
+}
+
+// mappingKeyIndex returns the index of key in a mapping node's content, or -1
+func mappingKeyIndex(node *yaml.Node, key string) int {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// MarshalYAML writes members with an expiry date as time-limited grants
+func (s Scope) MarshalYAML() (any, error) {
+	type plain Scope
+	if len(s.Until) == 0 {
+		return plain(s), nil
+	}
+
+	var node yaml.Node
+	if err := node.Encode(plain(s)); err != nil {
+		return nil, err
+	}
+	if members := mappingValue(&node, "members"); members != nil {
+		for i, item := range members.Content {
+			if until, ok := s.Until[item.Value]; ok {
+				members.Content[i] = newGrantNode(item.Value, until)
+			}
+		}
+	}
+	return &node, nil
+}
+
+// newGrantNode builds the {member, until} entry of a time-limited grant
+func newGrantNode(entry string, until time.Time) *yaml.Node {
+	return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Style: yaml.FlowStyle, Content: []*yaml.Node{
+		{Kind: yaml.ScalarNode, Tag: "!!str", Value: "member"},
+		{Kind: yaml.ScalarNode, Tag: "!!str", Value: entry},
+		{Kind: yaml.ScalarNode, Tag: "!!str", Value: "until"},
+		{Kind: yaml.ScalarNode, Tag: "!!timestamp", Value: until.Format(DateFormat)},
+	}}
+}
+
+// ActiveMembers returns the scope's member entries whose grants have not expired at now
+func (s *Scope) ActiveMembers(now time.Time) []string {
+	return Filter(s.Members, func(entry string) bool {
+		until, limited := s.Until[entry]
+		return !limited || !grantExpired(until, now)
+	})
+}
+
+// AccessGrant reports the state of one time-limited scope member entry
+type AccessGrant struct {
+	Until         time.Time `json:"until"`
+	Scope         string    `json:"scope"`
+	Member        string    `json:"member"`         // Member ID or @group
+	State         KeyState  `json:"state"`          // ok, expiring or expired, as for keys
+	DaysRemaining int       `json:"days_remaining"` // Negative once access has ended
+}
+
+// AccessGrants classifies every time-limited grant in the manifest at now
+func (m *Manifest) AccessGrants(now time.Time) []AccessGrant {
+	grants := []AccessGrant{}
+	for _, scope := range m.Scopes {
+		for _, entry := range scope.Members {
+			until, limited := scope.Until[entry]
+			if !limited {
+				continue
+			}
+			grant := AccessGrant{
+				Until:         until,
+				Scope:         scope.Name,
+				Member:        entry,
+				DaysRemaining: int(until.AddDate(0, 0, 1).Sub(now).Hours() / HoursPerDay),
+				State:         KeyOK,
+			}
+			switch {
+			case grantExpired(until, now):
+				grant.State = KeyExpired
+			case grantExpired(until, now.AddDate(0, 0, accessWarningDays)):
+				grant.State = KeyExpiring
+			}
+			grants = append(grants, grant)
+		}
+	}
+	return grants
+}
+
+// expiredGrantMembers returns the IDs of members whose grants to scope, or
+// to a scope it inherits from, have expired at now
+func (m *Manifest) expiredGrantMembers(scope *Scope, now time.Time) *Set[string] {
+	expired := NewSet[string]()
+	seen := NewSet[string]()
+	var walk func(*Scope)
+	walk = func(scope *Scope) {
+		if scope == nil || seen.Contains(scope.Name) {
+			return
+		}
+		seen.Add(scope.Name)
+		for _, entry := range scope.Members {
+			if until, limited := scope.Until[entry]; limited && grantExpired(until, now) {
+				ids, _ := m.ExpandMembers([]string{entry}) //nolint:errcheck // Validation reports unknown groups and cycles
+				for _, id := range ids {
+					expired.Add(id)
+				}
+			}
+		}
+		for _, name := range scope.InheritsMembersFrom {
+			walk(m.FindScope(name))
+		}
+	}
+	walk(scope)
+	return expired
+}
+
+// GrantScopeAccess grants an existing member access to a scope through the given date
+func (s *SopsManager) GrantScopeAccess(scope, memberID, until string) error {
+	date, err := parseGrantDate(until)
+	if err != nil {
+		return NewManifestError("validate", "scope "+scope, err)
+	}
+	if grantExpired(date, time.Now()) {
+		return NewManifestError("validate", "scope "+scope, fmt.Errorf("until date %s is in the past", until))
+	}
+
+	message := fmt.Sprintf("Added %s to scope %s until %s", memberID, scope, until)
+	return s.changeScope("add-member", scope, message, func(editor *ManifestEditor) error {
+		return editor.AddScopeGrant(scope, memberID, date)
+	})
+}
+
+// CheckAccessGrants reports time-limited grants that have ended or end soon
+func (s *SopsManager) CheckAccessGrants() error {
+	manifest, err := LoadManifest(s.configPath)
+	if err != nil {
+		return fmt.Errorf(FailedToLoadManifestMsg, err)
+	}
+
+	grants := manifest.AccessGrants(time.Now())
+	if len(grants) == 0 {
+		s.reporter.Infof("No time-limited access")
+		return nil
+	}
+
+	expired := 0
+	for _, grant := range grants {
+		until := grant.Until.Format(DateFormat)
+		switch grant.State {
+		case KeyExpired:
+			expired++
+			s.reporter.Infof("❌ %s in %s: access ended %s", grant.Member, grant.Scope, until)
+		case KeyExpiring:
+			s.reporter.Infof("⚠️  %s in %s: access ends in %d days (until %s)", grant.Member, grant.Scope, grant.DaysRemaining, until)
+		case KeyOK:
+			s.reporter.Infof("✅ %s in %s: access until %s", grant.Member, grant.Scope, until)
+		}
+	}
+
+	if expired > 0 {
+		s.reporter.Infof("\n%d grants have ended. Run 'sistry plan' and 'sistry apply' to re-encrypt files without them.", expired)
+	}
+	return nil
+}
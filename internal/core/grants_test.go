@@ -0,0 +1,180 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// writeGrantManifest writes a manifest where bob's production access ended
+// in 2020 and carol's lasts until 2999
+func writeGrantManifest(t *testing.T, dir string) string {
+	t.Helper()
+	return writeTestFile(t, dir, "sopsistry.yaml", `members:
+  - id: alice
+    age_key: `+testRecipientA+`
+  - id: bob
+    age_key: `+testRecipientB+`
+  - id: carol
+    age_key: `+testRecipientC+`
+scopes:
+  - name: production
+    patterns: [`+filepath.Join(dir, "prod.env")+`]
+    members:
+      - alice
+      - {member: bob, until: 2020-01-31}
+      - member: carol
+        until: 2999-12-31
+`)
+}
+
+func TestManifest_ExpiredGrantsAreIgnored(t *testing.T) {
+	t.Parallel()
+
+	// Given: a scope with an ended and an ongoing grant
+	manifest := loadManifestOrFail(t, writeGrantManifest(t, t.TempDir()))
+
+	// When: resolving its members
+	members, err := manifest.GetScopeMembers("production")
+
+	// Then: only the ended grant is left out
+	requireNoError(t, err, "resolving members should succeed")
+	if ids := MapSlice(members, func(m Member) string { return m.ID }); !slices.Equal(ids, []string{"alice", "carol"}) {
+		t.Errorf("expected alice and carol, got %v", ids)
+	}
+	if got := manifest.FindScope("production").Until["bob"]; !got.Equal(time.Date(2020, 1, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected bob's grant to end 2020-01-31, got %v", got)
+	}
+}
+
+func TestManifest_AccessGrants(t *testing.T) {
+	t.Parallel()
+
+	// Given: grants ending on the 31st
+	manifest := loadManifestOrFail(t, writeGrantManifest(t, t.TempDir()))
+	scope := manifest.FindScope("production")
+	scope.Until["carol"] = time.Date(2026, 5, 31, 0, 0, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		name string
+		now  time.Time
+		want KeyState
+	}{
+		{"weeks before", time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC), KeyOK},
+		{"days before", time.Date(2026, 5, 28, 12, 0, 0, 0, time.UTC), KeyExpiring},
+		{"on the last day", time.Date(2026, 5, 31, 23, 0, 0, 0, time.UTC), KeyExpiring},
+		{"the day after", time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC), KeyExpired},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// When: classifying grants at now
+			grants := manifest.AccessGrants(tc.now)
+
+			// Then: carol's grant has the expected state
+			if len(grants) != 2 || grants[1].Member != "carol" || grants[1].State != tc.want {
+				t.Errorf("expected carol's grant to be %s, got %+v", tc.want, grants)
+			}
+		})
+	}
+}
+
+func TestPlanner_RevokesExpiredAccess(t *testing.T) {
+	t.Parallel()
+
+	// Given: prod.env is still encrypted for bob, whose grant has ended
+	tempDir := t.TempDir()
+	prodFile := writeTestFile(t, tempDir, "prod.env", sopsDotenvContent(testRecipientA, testRecipientB, testRecipientC))
+	manifest := loadManifestOrFail(t, writeGrantManifest(t, tempDir))
+
+	// When: planning
+	plan, err := NewPlanner("sops").ComputePlan(manifest)
+
+	// Then: the file is re-encrypted to remove bob, and the reason is given
+	requireNoError(t, err, "computing plan should succeed")
+	action := findActionForFile(t, plan, prodFile)
+	if action.Type != ActionReencrypt || action.Description != "Access expired, re-encrypt to remove" {
+		t.Errorf("expected an access expiry re-encryption, got %s: %s", action.Type, action.Description)
+	}
+	if !slices.Equal(action.ExpiredAccess, []string{"bob"}) {
+		t.Errorf("expected bob's access to expire, got %v", action.ExpiredAccess)
+	}
+}
+
+func TestPlanner_ClassifiesGrantsAtItsOwnTime(t *testing.T) {
+	t.Parallel()
+
+	// Given: prod.env is encrypted for alice, bob and carol, and bob's grant ends 2020-01-31
+	tempDir := t.TempDir()
+	prodFile := writeTestFile(t, tempDir, "prod.env", sopsDotenvContent(testRecipientA, testRecipientB, testRecipientC))
+	manifest := loadManifestOrFail(t, writeGrantManifest(t, tempDir))
+
+	for _, tc := range []struct {
+		name    string
+		now     time.Time
+		want    ActionType
+		expired []string
+	}{
+		{"on the last day", time.Date(2020, 1, 31, 23, 0, 0, 0, time.UTC), ActionNoop, nil},
+		{"the day after", time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC), ActionReencrypt, []string{"bob"}},
+	} {
+		// When: planning at now
+		planner := NewPlanner("sops")
+		planner.now = tc.now
+		plan, err := planner.ComputePlan(manifest)
+
+		// Then: membership and the expired access agree with that time
+		requireNoError(t, err, "computing plan should succeed")
+		action := findActionForFile(t, plan, prodFile)
+		if action.Type != tc.want || !slices.Equal(action.ExpiredAccess, tc.expired) {
+			t.Errorf("%s: expected %s with expired access %v, got %s with %v", tc.name, tc.want, tc.expired, action.Type, action.ExpiredAccess)
+		}
+	}
+}
+
+func TestManifest_SaveKeepsGrants(t *testing.T) {
+	t.Parallel()
+
+	// Given: a loaded manifest with grants
+	dir := t.TempDir()
+	manifest := loadManifestOrFail(t, writeGrantManifest(t, dir))
+
+	// When: saving it
+	path := filepath.Join(dir, "saved.yaml")
+	requireNoError(t, manifest.Save(path), "saving should succeed")
+
+	// Then: grants are written as {member, until} entries and read back
+	data, err := os.ReadFile(path) //nolint:gosec // Test file in temp dir
+	requireNoError(t, err, "reading saved manifest should succeed")
+	if !containsString(string(data), "- {member: bob, until: 2020-01-31}") {
+		t.Errorf("expected bob's grant in saved manifest, got:\n%s", data)
+	}
+	saved := loadManifestOrFail(t, path)
+	if !slices.Equal(saved.FindScope("production").Members, []string{"alice", "bob", "carol"}) || len(saved.FindScope("production").Until) != 2 {
+		t.Errorf("expected grants to survive saving, got %+v", saved.FindScope("production"))
+	}
+}
+
+func TestSopsManager_GrantScopeAccess(t *testing.T) {
+	t.Parallel()
+
+	// Given: a development scope without carol
+	service, _, _ := setupScopeTest(t)
+
+	// When: granting carol access for a while, then revoking it
+	requireError(t, service.GrantScopeAccess("development", "carol", "2020-01-01"), "past dates should be refused")
+	requireError(t, service.GrantScopeAccess("development", "carol", "next week"), "invalid dates should be refused")
+	requireNoError(t, service.GrantScopeAccess("development", "carol", "2999-01-01"), "granting access should succeed")
+
+	// Then: the grant is saved, and can be removed like any member
+	scope := loadManifestOrFail(t, service.configPath).FindScope("development")
+	if !slices.Contains(scope.Members, "carol") || scope.Until["carol"].Year() != 2999 {
+		t.Errorf("expected carol's grant in development, got %+v", scope)
+	}
+	requireNoError(t, service.RemoveScopeMember("development", "carol"), "removing the grant should succeed")
+	if scope := loadManifestOrFail(t, service.configPath).FindScope("development"); slices.Contains(scope.Members, "carol") {
+		t.Errorf("expected carol's grant to be removed, got %v", scope.Members)
+	}
+}
@@ -0,0 +1,102 @@
+package core
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// groupPrefix marks a group reference among the members of a scope or group, e.g. "@backend"
+const groupPrefix = "@"
+
+// groupRef returns the group named by entry when entry is a group reference
+func groupRef(entry string) (string, bool) {
+	return strings.CutPrefix(entry, groupPrefix)
+}
+
+// ScopeAccess explains one way a member reaches a scope
+type ScopeAccess struct {
+	Scope         string   `json:"scope"`
+	Via           []string `json:"via,omitempty"`            // Groups leading to the member, outermost first; empty if listed directly
+	InheritedFrom string   `json:"inherited_from,omitempty"` // Scope the access is inherited from, if any
+}
+
+// String renders the access as "production (direct)", "production (via
+// @platform → @backend)" or "staging (inherited from production)"
+func (a ScopeAccess) String() string {
+	var how []string
+	if a.InheritedFrom != "" {
+		how = append(how, "inherited from "+a.InheritedFrom)
+	}
+	if len(a.Via) > 0 {
+		groups := MapSlice(a.Via, func(g string) string { return groupPrefix + g })
+		how = append(how, "via "+strings.Join(groups, " → "))
+	}
+	if len(how) == 0 {
+		how = append(how, "direct")
+	}
+	return a.Scope + " (" + strings.Join(how, " ") + ")"
+}
+
+// groupCycleError reports groups that contain themselves through nesting
+type groupCycleError struct {
+	cycle []string // Group names, starting and ending with the same group
+}
+
+func (e *groupCycleError) Error() string {
+	return "group cycle: " + strings.Join(MapSlice(e.cycle, func(g string) string { return groupPrefix + g }), " → ")
+}
+
+// ExpandMembers resolves member IDs and @group references into member IDs, in
+// order of first appearance. Unknown groups and group cycles are errors.
+func (m *Manifest) ExpandMembers(entries []string) ([]string, error) {
+	var ids []string
+	seen := NewSet[string]()
+	err := m.walkMembers(entries, nil, func(id string, _ []string) {
+		if !seen.Contains(id) {
+			seen.Add(id)
+			ids = append(ids, id)
+		}
+	})
+	return ids, err
+}
+
+// walkMembers calls visit for every member reached from entries, with the
+// chain of groups it was reached through
+func (m *Manifest) walkMembers(entries, via []string, visit func(id string, via []string)) error {
+	for _, entry := range entries {
+		group, isGroup := groupRef(entry)
+		if !isGroup {
+			visit(entry, via)
+			continue
+		}
+
+		if slices.Contains(via, group) {
+			return &groupCycleError{cycle: append(slices.Clone(via[slices.Index(via, group):]), group)}
+		}
+		members, ok := m.Groups[group]
+		if !ok {
+			return fmt.Errorf("group %s not found", group)
+		}
+		if err := m.walkMembers(members, append(slices.Clone(via), group), visit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MemberScopes returns every way member id reaches a scope at now, in manifest
+// order. Unresolvable groups and scopes are skipped; validation reports them.
+func (m *Manifest) MemberScopes(id string, now time.Time) []ScopeAccess {
+	var access []ScopeAccess
+	for i := range m.Scopes {
+		scope := &m.Scopes[i]
+		_ = m.walkScopeMembers(scope, now, nil, func(member string, via []string, from string) { //nolint:errcheck // Validation reports unknown groups, scopes and cycles
+			if member == id {
+				access = append(access, ScopeAccess{Scope: scope.Name, Via: via, InheritedFrom: from})
+			}
+		})
+	}
+	return access
+}
@@ -0,0 +1,138 @@
+package core
+
+import (
+	"bytes"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func createGroupedManifest() *Manifest {
+	return &Manifest{
+		Members: []Member{
+			{ID: "alice", AgeKey: testRecipientA},
+			{ID: "bob", AgeKey: testRecipientB},
+			{ID: "carol", AgeKey: testRecipientC},
+		},
+		Groups: map[string][]string{
+			"backend":  {"alice", "bob"},
+			"platform": {"@backend", "carol"},
+		},
+		Scopes: []Scope{
+			{Name: "production", Patterns: []string{"prod.env"}, Members: []string{"@platform", "alice"}},
+			{Name: "development", Patterns: []string{"dev.env"}, Members: []string{"@backend"}},
+		},
+	}
+}
+
+func TestManifest_ScopeMembersExpandsNestedGroups(t *testing.T) {
+	t.Parallel()
+
+	// Given: a scope referencing a group that nests another group
+	manifest := createGroupedManifest()
+
+	// When: resolving its members
+	members, err := manifest.GetScopeMembers("production")
+
+	// Then: every member is included once, in order of first appearance
+	requireNoError(t, err, "expanding groups should succeed")
+	ids := MapSlice(members, func(m Member) string { return m.ID })
+	if !slices.Equal(ids, []string{"alice", "bob", "carol"}) {
+		t.Errorf("expected alice, bob and carol, got %v", ids)
+	}
+}
+
+func TestManifest_ExpandMembersDetectsCycles(t *testing.T) {
+	t.Parallel()
+
+	// Given: groups that contain each other
+	manifest := createGroupedManifest()
+	manifest.Groups["backend"] = append(manifest.Groups["backend"], "@platform")
+
+	// When: expanding a scope referencing them
+	_, err := manifest.GetScopeMembers("development")
+
+	// Then: the cycle is reported rather than recursing forever
+	requireError(t, err, "cyclic groups should fail")
+	if !containsString(err.Error(), "group cycle: @backend → @platform → @backend") {
+		t.Errorf("expected cycle in error, got: %v", err)
+	}
+}
+
+func TestManifest_MemberScopesExplainsAccess(t *testing.T) {
+	t.Parallel()
+
+	// Given: alice is listed directly and through nested groups
+	manifest := createGroupedManifest()
+
+	// When: listing the manifest
+	var output bytes.Buffer
+	manifest.Display(&output)
+
+	// Then: every route to every scope is shown
+	want := "Scopes: production (via @platform → @backend), production (direct), development (via @backend)"
+	if !containsString(output.String(), want) {
+		t.Errorf("expected %q in output, got: %s", want, output.String())
+	}
+	if !containsString(output.String(), "carol: "+shortenKey(testRecipientC)+"\n    Scopes: production (via @platform)") {
+		t.Errorf("expected carol's access via platform, got: %s", output.String())
+	}
+}
+
+func TestLoadManifest_ValidatesGroups(t *testing.T) {
+	t.Parallel()
+
+	// Given: a group with an unknown member, a cycle, and a scope referencing a missing group
+	path := writeTestFile(t, t.TempDir(), "sopsistry.yaml", `members:
+  - id: alice
+    age_key: `+testRecipientA+`
+groups:
+  backend: [alice, mallory, "@ops"]
+  ops: ["@backend"]
+scopes:
+  - name: default
+    patterns: ["*.env"]
+    members: ["@frontend"]
+`)
+
+	// When: loading it
+	_, err := LoadManifest(path)
+
+	// Then: each problem is reported
+	requireError(t, err, "invalid groups should be refused")
+	for _, want := range []string{
+		`5:20: group backend references unknown member "mallory"`,
+		"5:3: group backend is part of a group cycle",
+		"6:3: group ops is part of a group cycle",
+		`10:15: scope default references unknown group "frontend"`,
+	} {
+		if !containsString(err.Error(), want) {
+			t.Errorf("expected %q in error, got: %v", want, err)
+		}
+	}
+}
+
+func TestManifestEditor_GroupReferences(t *testing.T) {
+	t.Parallel()
+
+	// Given: a manifest with a backend group
+	path := filepath.Join(t.TempDir(), "sopsistry.yaml")
+	requireNoError(t, createGroupedManifest().Save(path), "saving manifest should succeed")
+	editor, err := EditManifest(path)
+	requireNoError(t, err, "opening manifest should succeed")
+
+	// When: referencing groups from a scope and removing a member
+	requireError(t, editor.AddScopeMember("development", "@frontend"), "unknown groups should be refused")
+	requireNoError(t, editor.AddScopeMember("development", "@platform"), "adding a group should succeed")
+	requireNoError(t, editor.RemoveMember("bob"), "removing bob should succeed")
+	requireNoError(t, editor.Save(), "saving should succeed")
+
+	// Then: the scope references the group and bob is gone from every group
+	manifest := loadManifestOrFail(t, path)
+	if !slices.Equal(manifest.FindScope("development").Members, []string{"@backend", "@platform"}) {
+		t.Errorf("expected both groups in development, got %v", manifest.FindScope("development").Members)
+	}
+	if !slices.Equal(manifest.Groups["backend"], []string{"alice"}) {
+		t.Errorf("expected bob removed from backend, got %v", manifest.Groups["backend"])
+	}
+}
@@ -0,0 +1,88 @@
+package core
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// scopeCycleError reports scopes that inherit members from themselves
+type scopeCycleError struct {
+	cycle []string // Scope names, starting and ending with the same scope
+}
+
+func (e *scopeCycleError) Error() string {
+	return "inherits_members_from cycle: " + strings.Join(e.cycle, " → ")
+}
+
+// ScopeMembership returns the members of scope at now, including those
+// inherited through inherits_members_from but not suspended members, and maps
+// each member that is only inherited to the scope listing it
+func (m *Manifest) ScopeMembership(scope *Scope, now time.Time) ([]Member, map[string]string, error) {
+	var ids []string
+	inherited := make(map[string]string)
+	seen := NewSet[string]()
+	err := m.walkScopeMembers(scope, now, nil, func(id string, _ []string, from string) {
+		if seen.Contains(id) {
+			return
+		}
+		seen.Add(id)
+		ids = append(ids, id)
+		if from != "" {
+			inherited[id] = from
+		}
+	})
+	if err != nil {
+		return nil, nil, NewManifestError("validate", "scope "+scope.Name, err)
+	}
+
+	members := make([]Member, 0, len(ids))
+	for _, id := range ids {
+		member := m.FindMember(id)
+		if member == nil {
+			return nil, nil, NewManifestError("validate", "scope "+scope.Name, fmt.Errorf("member %s not found", id))
+		}
+		if member.IsSuspended() {
+			delete(inherited, id)
+			continue
+		}
+		members = append(members, *member)
+	}
+	return members, inherited, nil
+}
+
+// walkScopeMembers calls visit for every member of scope, its own members
+// first, then the human members of the scopes it inherits from, skipping
+// grants expired at now. from is empty for the scope's own members, and
+// otherwise names the inherited scope listing them.
+func (m *Manifest) walkScopeMembers(scope *Scope, now time.Time, chain []string, visit func(id string, via []string, from string)) error {
+	from := ""
+	if len(chain) > 0 {
+		from = scope.Name
+	}
+	err := m.walkMembers(scope.ActiveMembers(now), nil, func(id string, via []string) {
+		// Machine members only read the scopes listing them
+		if from == "" || !m.isMachine(id) {
+			visit(id, via, from)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	chain = append(slices.Clone(chain), scope.Name)
+	for _, name := range scope.InheritsMembersFrom {
+		if slices.Contains(chain, name) {
+			return &scopeCycleError{cycle: append(slices.Clone(chain[slices.Index(chain, name):]), name)}
+		}
+		parent := m.FindScope(name)
+		if parent == nil {
+			return fmt.Errorf("scope %s inherits members from unknown scope %s", scope.Name, name)
+		}
+		if err := m.walkScopeMembers(parent, now, chain, visit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
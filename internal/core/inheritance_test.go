@@ -0,0 +1,141 @@
+package core
+
+import (
+	"bytes"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// createHierarchicalManifest returns production, staging and development
+// scopes where each lower environment inherits the readers of the one above
+func createHierarchicalManifest(dir string) *Manifest {
+	return &Manifest{
+		Members: []Member{
+			{ID: "alice", AgeKey: testRecipientA},
+			{ID: "bob", AgeKey: testRecipientB},
+			{ID: "carol", AgeKey: testRecipientC},
+		},
+		Scopes: []Scope{
+			{Name: "production", Patterns: []string{filepath.Join(dir, "prod.env")}, Members: []string{"alice"}},
+			{Name: "staging", Patterns: []string{filepath.Join(dir, "staging.env")}, Members: []string{"bob"}, InheritsMembersFrom: []string{"production"}},
+			{Name: "development", Patterns: []string{filepath.Join(dir, "dev.env")}, Members: []string{"carol", "bob"}, InheritsMembersFrom: []string{"staging"}},
+		},
+	}
+}
+
+func TestManifest_ScopeMembershipInheritsTransitively(t *testing.T) {
+	t.Parallel()
+
+	// Given: development inherits staging, which inherits production
+	manifest := createHierarchicalManifest(t.TempDir())
+
+	// When: resolving development's members
+	members, inherited, err := manifest.ScopeMembership(manifest.FindScope("development"), time.Now())
+
+	// Then: its own members come first, and only alice is inherited
+	requireNoError(t, err, "resolving members should succeed")
+	ids := MapSlice(members, func(m Member) string { return m.ID })
+	if !slices.Equal(ids, []string{"carol", "bob", "alice"}) {
+		t.Errorf("expected carol, bob and alice, got %v", ids)
+	}
+	if len(inherited) != 1 || inherited["alice"] != "production" {
+		t.Errorf("expected alice inherited from production, got %v", inherited)
+	}
+}
+
+func TestManifest_ScopeInheritanceCycle(t *testing.T) {
+	t.Parallel()
+
+	// Given: production inherits from development, closing a cycle
+	manifest := createHierarchicalManifest(t.TempDir())
+	manifest.Scopes[0].InheritsMembersFrom = []string{"development"}
+
+	// When: resolving members
+	_, err := manifest.GetScopeMembers("staging")
+
+	// Then: the cycle is reported
+	requireError(t, err, "cyclic inheritance should fail")
+	if !containsString(err.Error(), "inherits_members_from cycle: staging → production → development → staging") {
+		t.Errorf("expected cycle in error, got: %v", err)
+	}
+}
+
+func TestPlanner_ShowsInheritedMembers(t *testing.T) {
+	t.Parallel()
+
+	// Given: a staging file to encrypt
+	tempDir := t.TempDir()
+	stagingFile := writeTestFile(t, tempDir, "staging.env", "KEY=value\n")
+	manifest := createHierarchicalManifest(tempDir)
+
+	// When: planning
+	plan, err := NewPlanner("sops").ComputePlan(manifest)
+
+	// Then: production's readers are recipients and shown as inherited
+	requireNoError(t, err, "computing plan should succeed")
+	action := findActionForFile(t, plan, stagingFile)
+	if !slices.Equal(action.AddedRecipients, []string{"bob", "alice"}) {
+		t.Errorf("expected bob and alice, got %v", action.AddedRecipients)
+	}
+
+	var output bytes.Buffer
+	plan.Display(NewWriterReporter(&output))
+	if !containsString(output.String(), "Inherited: alice (from production)") {
+		t.Errorf("expected inherited members in plan, got: %s", output.String())
+	}
+}
+
+func TestLoadManifest_ValidatesInheritance(t *testing.T) {
+	t.Parallel()
+
+	// Given: a scope inheriting from a missing scope, and two scopes inheriting from each other
+	path := writeTestFile(t, t.TempDir(), "sopsistry.yaml", `members:
+  - id: alice
+    age_key: `+testRecipientA+`
+scopes:
+  - name: a
+    patterns: ["a.env"]
+    members: [alice]
+    inherits_members_from: [b]
+  - name: b
+    patterns: ["b.env"]
+    members: [alice]
+    inherits_members_from: [a, c]
+`)
+
+	// When: loading it
+	_, err := LoadManifest(path)
+
+	// Then: the unknown scope and the cycle are reported
+	requireError(t, err, "invalid inheritance should be refused")
+	for _, want := range []string{
+		`12:32: scope b inherits members from unknown scope "c"`,
+		"8:28: scope a is part of an inherits_members_from cycle: a → b → a",
+	} {
+		if !containsString(err.Error(), want) {
+			t.Errorf("expected %q in error, got: %v", want, err)
+		}
+	}
+}
+
+func TestManifestEditor_RenameScopeUpdatesInheritance(t *testing.T) {
+	t.Parallel()
+
+	// Given: staging inherits from production
+	path := filepath.Join(t.TempDir(), "sopsistry.yaml")
+	requireNoError(t, createHierarchicalManifest("secrets").Save(path), "saving manifest should succeed")
+	editor, err := EditManifest(path)
+	requireNoError(t, err, "opening manifest should succeed")
+
+	// When: renaming production
+	requireNoError(t, editor.RenameScope("production", "prod"), "rename should succeed")
+	requireNoError(t, editor.Save(), "saving should succeed")
+
+	// Then: staging inherits from the renamed scope
+	manifest := loadManifestOrFail(t, path)
+	if !slices.Equal(manifest.FindScope("staging").InheritsMembersFrom, []string{"prod"}) {
+		t.Errorf("expected staging to inherit from prod, got %v", manifest.FindScope("staging").InheritsMembersFrom)
+	}
+}
@@ -0,0 +1,309 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// requestsDir holds the requests written by 'sistry join', next to the
+// manifest. It is committed, so requests are reviewed like any other change.
+const requestsDir = ".sopsistry/requests"
+
+// JoinRequest asks for the requester to be added as a member. It is written by
+// 'sistry join' and turned into a member by 'sistry approve'.
+//
+// Requests are signed with the requester's SSH key, so 'sistry approve'
+// detects a request changed after it was written. Anyone who can commit could
+// still sign a request with their own key: the approver confirms the SSH key
+// fingerprint and the age key with the newcomer out of band.
+type JoinRequest struct {
+	Requested  time.Time `yaml:"requested" json:"requested"`
+	ID         string    `yaml:"id" json:"id"`
+	AgeKey     string    `yaml:"age_key" json:"age_key"`
+	Scopes     []string  `yaml:"scopes" json:"scopes"`
+	SigningKey string    `yaml:"signing_key" json:"signing_key"` // SSH public key the request is signed with
+	Signature  string    `yaml:"signature" json:"signature"`     // 'ssh-keygen -Y sign' signature of signedContent
+}
+
+// Approval records who let a member in with 'sistry approve', and when
+type Approval struct {
+	By string    `yaml:"by" json:"by"`
+	At time.Time `yaml:"at" json:"at"`
+}
+
+// requestPath returns where the join request of member id is kept
+func (s *SopsManager) requestPath(id string) string {
+	return filepath.Join(filepath.Dir(s.configPath), requestsDir, id+".yaml")
+}
+
+// saveJoinRequest writes the request to its file, creating the requests directory
+func (s *SopsManager) saveJoinRequest(request *JoinRequest) (string, error) {
+	path := s.requestPath(request.ID)
+	if err := os.MkdirAll(filepath.Dir(path), RequestsDirMode); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+
+	data, err := yaml.Marshal(request)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal join request: %w", err)
+	}
+	if err := os.WriteFile(path, data, GitignoreFileMode); err != nil {
+		return "", fmt.Errorf("failed to write join request: %w", err)
+	}
+	return path, nil
+}
+
+// loadJoinRequest reads the pending join request of member id
+func (s *SopsManager) loadJoinRequest(id string) (*JoinRequest, string, error) {
+	if _, err := NewMemberID(id); err != nil {
+		return nil, "", NewManifestError("validate", "member "+id, err)
+	}
+
+	path := s.requestPath(id)
+	data, err := os.ReadFile(path) //nolint:gosec // Path built from a validated member ID
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, path, fmt.Errorf("no pending join request for %s in %s", id, filepath.Dir(path))
+	}
+	if err != nil {
+		return nil, path, fmt.Errorf("failed to read join request: %w", err)
+	}
+
+	var request JoinRequest
+	if err := yaml.Unmarshal(data, &request); err != nil {
+		return nil, path, fmt.Errorf("failed to parse join request %s: %w", path, err)
+	}
+	if request.ID != id {
+		return nil, path, fmt.Errorf("join request %s is for %q, not %s", path, request.ID, id)
+	}
+	return &request, path, nil
+}
+
+// validateJoinRequest checks that the requester can still be added as asked
+func validateJoinRequest(manifest *Manifest, request *JoinRequest) error {
+	if _, err := NewMemberID(request.ID); err != nil {
+		return NewManifestError("validate", "member "+request.ID, err)
+	}
+	if manifest.FindMember(request.ID) != nil {
+		return fmt.Errorf("member %s already exists", request.ID)
+	}
+	if _, err := NewAgePublicKey(request.AgeKey); err != nil {
+		return NewManifestError("validate", "member "+request.ID, err)
+	}
+	if ids := manifest.MemberIDsForKey(request.AgeKey); len(ids) > 0 {
+		return fmt.Errorf("age key %s already belongs to %s", shortenKey(request.AgeKey), strings.Join(ids, ", "))
+	}
+	if len(request.Scopes) == 0 {
+		return NewManifestError("validate", "member "+request.ID, fmt.Errorf("no scope to join"))
+	}
+	for _, scope := range request.Scopes {
+		if manifest.FindScope(scope) == nil {
+			return NewManifestError("validate", "member "+request.ID, fmt.Errorf("scope %s not found", scope))
+		}
+	}
+	return nil
+}
+
+// Join asks for the current user to be added to the given scopes, or to the
+// manifest's default scopes when none are given. The age key is set up as by
+// 'sistry init', and a request signed with sshKey (see sshSigningKey) is
+// written for an admin to approve.
+func (s *SopsManager) Join(sshKey string, scopes ...string) error {
+	manifest, err := LoadManifest(s.configPath)
+	if err != nil {
+		return fmt.Errorf(FailedToLoadManifestMsg, err)
+	}
+
+	memberID, err := s.getCurrentMemberID()
+	if err != nil {
+		return err
+	}
+	if manifest.FindMember(memberID) != nil {
+		return fmt.Errorf("you are already member %s; use 'sistry key add' to register another device", memberID)
+	}
+	if len(scopes) == 0 {
+		scopes = manifest.MemberDefaultScopes()
+	}
+	if len(scopes) == 0 {
+		return NewManifestError("validate", "member "+memberID,
+			fmt.Errorf("no scope to join: use --scope, or ask an admin to set default_scopes in %s", s.configPath))
+	}
+
+	signingKey, err := s.sshSigningKey(sshKey)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.setupEnvironment(); err != nil {
+		return err
+	}
+	publicKey, err := s.setupAgeKey(manifest, memberID)
+	if err != nil {
+		return err
+	}
+
+	request := &JoinRequest{Requested: time.Now().UTC().Truncate(time.Second), ID: memberID, AgeKey: publicKey, Scopes: scopes}
+	if err := validateJoinRequest(manifest, request); err != nil {
+		return err
+	}
+	if err := request.sign(signingKey); err != nil {
+		return err
+	}
+
+	path, err := s.saveJoinRequest(request)
+	if err != nil {
+		return err
+	}
+
+	if s.jsonOutput {
+		return WriteJSONResult(s.reporter.Out(), "join", JoinResult{Request: path, JoinRequest: *request})
+	}
+
+	s.reporter.Infof("Wrote join request %s (scopes: %s)", path, strings.Join(scopes, ", "))
+	s.reporter.Infof("Age key: %s", publicKey)
+	s.reporter.Infof("Signed with SSH key %s", request.SigningKeyFingerprint())
+	s.reporter.Infof("Commit the request and ask an admin to run 'sistry approve %s'; they will ask you to confirm both keys", memberID)
+	return nil
+}
+
+// Approve adds the member asked for in a pending join request, recording the
+// member whose key is on this machine as approver, and shows the resulting
+// plan. Unless skipConfirmation is set, the full age key is shown and the
+// approver must confirm having checked it with the requester out of band.
+func (s *SopsManager) Approve(id string, skipConfirmation bool) error { //nolint:revive // skipConfirmation is a legitimate CLI flag parameter
+	request, path, err := s.loadJoinRequest(id)
+	if err != nil {
+		return err
+	}
+	if err := request.Verify(); err != nil {
+		return NewKeyError("verify", path, err)
+	}
+
+	manifest, err := LoadManifest(s.configPath)
+	if err != nil {
+		return fmt.Errorf(FailedToLoadManifestMsg, err)
+	}
+	if err := validateJoinRequest(manifest, request); err != nil {
+		return err
+	}
+
+	approver, err := s.approvingMember(manifest)
+	if err != nil {
+		return err
+	}
+	if !skipConfirmation {
+		if err := s.confirmJoinKey(request); err != nil {
+			return err
+		}
+	}
+
+	member := Member{
+		ID:       id,
+		AgeKey:   request.AgeKey,
+		Created:  request.Requested,
+		Approved: &Approval{By: approver, At: time.Now().UTC().Truncate(time.Second)},
+	}
+	if err := s.saveNewMember(member, request.Scopes); err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("member added, but removing the approved request failed: %w", err)
+	}
+
+	plan, err := s.computePlan(false)
+	if err != nil {
+		return fmt.Errorf("member added, but computing the plan failed: %w", err)
+	}
+
+	if s.jsonOutput {
+		return WriteJSONResult(s.reporter.Out(), "approve", ApprovalResult{
+			MemberResult: MemberResult{Member: id, Change: "approved", Scopes: request.Scopes},
+			ApprovedBy:   approver,
+			Plan:         plan.Result(""),
+		})
+	}
+
+	s.reporter.Infof("Approved %s (scopes: %s) with age key %s; removed %s", id, strings.Join(request.Scopes, ", "), request.AgeKey, path)
+	plan.Display(s.reporter)
+	if plan.HasChanges() {
+		s.reporter.Infof("Run 'sistry apply' to re-encrypt files for them")
+	}
+	return nil
+}
+
+// approvingMember returns the ID of the member whose private key is on this
+// machine. Only active people can approve, and since the user name can be set
+// by anyone, the approver is identified by key rather than by name.
+func (s *SopsManager) approvingMember(manifest *Manifest) (string, error) {
+	keys, err := s.localKeys()
+	if err != nil {
+		return "", err
+	}
+
+	var ids []string
+	for _, key := range keys {
+		ids = append(ids, manifest.MemberIDsForKey(key.PublicKey)...)
+	}
+	ids = slices.Compact(slices.Sorted(slices.Values(ids)))
+	switch len(ids) {
+	case 0:
+		return "", NewKeyError("approve", s.secretsDir, fmt.Errorf("none of the private keys in %s belongs to a member; only members can approve", s.secretsDir))
+	case 1:
+	default:
+		return "", NewKeyError("approve", strings.Join(ids, ", "), fmt.Errorf("the private keys in %s belong to several members (%s)", s.secretsDir, strings.Join(ids, ", ")))
+	}
+
+	member := manifest.FindMember(ids[0])
+	switch {
+	case member.IsSuspended():
+		return "", fmt.Errorf("member %s is suspended and cannot approve", member.ID)
+	case member.IsMachine():
+		return "", fmt.Errorf("member %s is a machine and cannot approve", member.ID)
+	}
+	return member.ID, nil
+}
+
+// confirmJoinKey shows the requested age key in full and the SSH key the
+// request is signed with, and asks the approver to confirm both were checked
+// with the requester
+func (s *SopsManager) confirmJoinKey(request *JoinRequest) error {
+	if s.jsonOutput {
+		return fmt.Errorf("--json cannot prompt for confirmation; confirm the age and SSH keys with %s, then pass --yes to approve", request.ID)
+	}
+
+	s.reporter.Infof("%s asks to join %s (requested %s) with age key:", request.ID, strings.Join(request.Scopes, ", "), request.Requested.Format(DateFormat))
+	_, _ = fmt.Fprintln(s.reporter.Out(), request.AgeKey) // Shown even in quiet mode
+	_, _ = fmt.Fprintf(s.reporter.Out(), "signed with SSH key %s\n", request.SigningKeyFingerprint())
+	_, _ = fmt.Fprintf(s.reporter.Out(), "\nConfirm with %s, outside this repository, that these are their keys. Approve? [y/N]: ", request.ID)
+	var response string
+	_, _ = fmt.Scanln(&response) // User input, ignore errors
+	if response != "y" && response != "Y" {
+		s.reporter.Infof("Cancelled")
+		return ErrCancelled
+	}
+	return nil
+}
+
+// Reject discards a pending join request
+func (s *SopsManager) Reject(id string) error {
+	_, path, err := s.loadJoinRequest(id)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to remove join request: %w", err)
+	}
+
+	if s.jsonOutput {
+		return WriteJSONResult(s.reporter.Out(), "reject", MemberResult{Member: id, Change: "rejected"})
+	}
+
+	s.reporter.Infof("Rejected the join request of %s; removed %s", id, path)
+	return nil
+}
@@ -0,0 +1,152 @@
+package core
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// joinSignatureNamespace keeps join request signatures from being valid for
+// anything else signed with the same SSH key, such as git commits
+const joinSignatureNamespace = "sopsistry-join"
+
+// defaultSSHKeys are tried in order when neither --ssh-key nor git's SSH
+// signing key is set
+var defaultSSHKeys = []string{"id_ed25519", "id_ecdsa", "id_rsa"}
+
+// signedContent is what the request signature covers
+func (r *JoinRequest) signedContent() []byte {
+	return fmt.Appendf(nil, "sopsistry join request\nid: %s\nage_key: %s\nscopes: %s\nrequested: %s\n",
+		r.ID, r.AgeKey, strings.Join(r.Scopes, ","), r.Requested.UTC().Format(time.RFC3339))
+}
+
+// sign signs the request with the SSH key at keyPath, a private key or the
+// public key of a key held by ssh-agent
+func (r *JoinRequest) sign(keyPath string) error {
+	if err := ensureBinaryAvailable(SSHKeygenBinary, "Please install OpenSSH"); err != nil {
+		return NewKeyError("sign", keyPath, err)
+	}
+
+	publicKeyPath := keyPath
+	if !strings.HasSuffix(keyPath, ".pub") {
+		publicKeyPath += ".pub"
+	}
+	publicKey, err := os.ReadFile(publicKeyPath) //nolint:gosec // SSH key chosen by the user
+	if err != nil {
+		return NewKeyError("sign", keyPath, fmt.Errorf("failed to read SSH public key: %w", err))
+	}
+
+	cmd := exec.Command(SSHKeygenBinary, "-Y", "sign", "-f", keyPath, "-n", joinSignatureNamespace) //nolint:gosec // SSH key chosen by the user
+	cmd.Stdin = bytes.NewReader(r.signedContent())
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	signature, err := cmd.Output()
+	if err != nil {
+		return NewKeyError("sign", keyPath, fmt.Errorf("ssh-keygen failed: %w: %s", err, strings.TrimSpace(stderr.String())))
+	}
+
+	r.SigningKey = strings.TrimSpace(string(publicKey))
+	r.Signature = string(signature)
+	return nil
+}
+
+// Verify checks that the request was signed with its SigningKey and not
+// changed since. Who holds that key must still be confirmed out of band.
+func (r *JoinRequest) Verify() error {
+	if r.SigningKey == "" || r.Signature == "" {
+		return fmt.Errorf("the request is not signed; ask %s to run 'sistry join' again", r.ID)
+	}
+	if err := ensureBinaryAvailable(SSHKeygenBinary, "Please install OpenSSH"); err != nil {
+		return err
+	}
+
+	dir, err := os.MkdirTemp("", "sopsistry-verify-")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }() //nolint:errcheck // Cleanup, failure not critical
+
+	signers := filepath.Join(dir, "allowed_signers")
+	signature := filepath.Join(dir, "request.sig")
+	if err := os.WriteFile(signers, []byte(r.ID+" "+r.SigningKey+"\n"), PrivateKeyFileMode); err != nil {
+		return fmt.Errorf("failed to write allowed signers: %w", err)
+	}
+	if err := os.WriteFile(signature, []byte(r.Signature), PrivateKeyFileMode); err != nil {
+		return fmt.Errorf("failed to write signature: %w", err)
+	}
+
+	cmd := exec.Command(SSHKeygenBinary, "-Y", "verify", "-f", signers, "-I", r.ID, "-n", joinSignatureNamespace, "-s", signature) //nolint:gosec // Paths are our own temporary files
+	cmd.Stdin = bytes.NewReader(r.signedContent())
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("signature does not match the request or its signing key: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// SigningKeyFingerprint returns the SHA256 fingerprint of the signing key, as
+// shown by 'ssh-keygen -l', followed by the key's comment if it has one
+func (r *JoinRequest) SigningKeyFingerprint() string {
+	fields := strings.Fields(r.SigningKey)
+	if len(fields) < 2 {
+		return r.SigningKey
+	}
+	blob, err := base64.StdEncoding.DecodeString(fields[1])
+	if err != nil {
+		return r.SigningKey
+	}
+	sum := sha256.Sum256(blob)
+	fingerprint := "SHA256:" + base64.RawStdEncoding.EncodeToString(sum[:])
+	if len(fields) > 2 {
+		fingerprint += " (" + strings.Join(fields[2:], " ") + ")"
+	}
+	return fingerprint
+}
+
+// sshSigningKey returns the SSH key to sign a join request with: keyPath if
+// given, else git's user.signingkey when git signs with SSH, else the first
+// default key in ~/.ssh
+func (s *SopsManager) sshSigningKey(keyPath string) (string, error) {
+	if keyPath != "" {
+		return keyPath, nil
+	}
+
+	dir := filepath.Dir(s.configPath)
+	format, _ := exec.Command("git", "-C", dir, "config", "--get", "gpg.format").Output() //nolint:errcheck,gosec // Unset means not SSH
+	if strings.TrimSpace(string(format)) == "ssh" {
+		key, _ := exec.Command("git", "-C", dir, "config", "--get", "user.signingkey").Output() //nolint:errcheck,gosec // Unset falls back to ~/.ssh
+		if path := strings.TrimSpace(string(key)); path != "" && !strings.HasPrefix(path, "key::") {
+			return expandHome(path)
+		}
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", NewKeyError("sign", "ssh", fmt.Errorf("failed to find home directory: %w", err))
+	}
+	for _, name := range defaultSSHKeys {
+		path := filepath.Join(home, ".ssh", name)
+		if _, err := os.Stat(path + ".pub"); err == nil {
+			return path, nil
+		}
+	}
+	return "", NewKeyError("sign", "ssh", fmt.Errorf("no SSH key to sign the join request with; pass --ssh-key, or set git's user.signingkey"))
+}
+
+// expandHome replaces a leading ~/ in path with the home directory
+func expandHome(path string) (string, error) {
+	rest, ok := strings.CutPrefix(path, "~/")
+	if !ok {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find home directory: %w", err)
+	}
+	return filepath.Join(home, rest), nil
+}
@@ -0,0 +1,185 @@
+package core
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// newTestSSHKey generates an SSH key pair without a passphrase and returns
+// the path of its private key
+func newTestSSHKey(t *testing.T) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "id_ed25519")
+	output, err := exec.Command(SSHKeygenBinary, "-q", "-t", "ed25519", "-N", "", "-C", "dave@laptop", "-f", path).CombinedOutput()
+	requireNoError(t, err, "generating an SSH key should succeed: "+string(output))
+	return path
+}
+
+// newTestJoinRequest returns a request from dave to read production, signed
+// with a new SSH key
+func newTestJoinRequest(t *testing.T) *JoinRequest {
+	t.Helper()
+
+	request := &JoinRequest{
+		Requested: time.Date(2026, 10, 1, 9, 30, 0, 0, time.UTC),
+		ID:        "dave",
+		AgeKey:    testRecipientD,
+		Scopes:    []string{"production"},
+	}
+	requireNoError(t, request.sign(newTestSSHKey(t)), "signing the request should succeed")
+	return request
+}
+
+// setupApprover gives service a secrets directory holding alice's private key
+func setupApprover(t *testing.T, service *SopsManager, tempDir string) {
+	t.Helper()
+
+	useFakeAgeKeygen(t)
+	service.secretsDir = filepath.Join(tempDir, ".secrets")
+	writeLocalKey(t, service.secretsDir, testRecipientA)
+}
+
+func TestSopsManager_ApproveJoinRequest(t *testing.T) {
+	// Not parallel: the fake age-keygen is put on PATH
+
+	// Given: a pending request from dave, and alice's key on this machine
+	service, output, tempDir := setupScopeTest(t)
+	setupApprover(t, service, tempDir)
+	t.Setenv("SOPSISTRY_USER_ID", "mallory")
+	request := newTestJoinRequest(t)
+	tampered := *request
+	tampered.AgeKey = testRecipientC + "x"
+	_, err := service.saveJoinRequest(&tampered)
+	requireNoError(t, err, "saving the tampered request should succeed")
+	if err := service.Approve("dave", true); ExitCode(err) != ExitKey {
+		t.Errorf("expected a changed request to be refused with a key error, got %v", err)
+	}
+	path, err := service.saveJoinRequest(request)
+	requireNoError(t, err, "saving the request should succeed")
+	service.jsonOutput = true
+	requireError(t, service.Approve("dave", false), "--json should require --yes")
+	service.jsonOutput = false
+
+	// When: approving it, with the key confirmed
+	requireNoError(t, service.Approve("dave", true), "approving should succeed")
+
+	// Then: dave is a member of production approved by alice, whatever the user
+	// name says, and the request is gone
+	manifest := loadManifestOrFail(t, service.configPath)
+	dave := manifest.FindMember("dave")
+	if dave == nil || dave.AgeKey != testRecipientD || dave.Approved == nil || dave.Approved.By != "alice" {
+		t.Fatalf("expected dave to be added with alice's approval, got %+v", dave)
+	}
+	if !slices.Contains(manifest.FindScope("production").Members, "dave") || slices.Contains(manifest.FindScope("development").Members, "dave") {
+		t.Errorf("expected dave only in production, got %+v", manifest.Scopes)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected %s to be removed, got %v", path, err)
+	}
+	if !containsString(output.String(), "Approved dave (scopes: production) with age key "+testRecipientD) {
+		t.Errorf("expected approval message with the full key, got: %s", output.String())
+	}
+	requireError(t, service.Approve("dave", true), "approving twice should fail")
+}
+
+func TestSopsManager_ApproveRequiresAnActiveMembersKey(t *testing.T) {
+	// Not parallel: the fake age-keygen is put on PATH
+
+	// Given: a pending request from dave
+	service, _, tempDir := setupScopeTest(t)
+	useFakeAgeKeygen(t)
+	service.secretsDir = filepath.Join(tempDir, ".secrets")
+	_, err := service.saveJoinRequest(newTestJoinRequest(t))
+	requireNoError(t, err, "saving the request should succeed")
+
+	// When: approving without a member's key, then with the key of suspended alice
+	errNoKey := service.Approve("dave", true)
+	writeLocalKey(t, service.secretsDir, testRecipientA)
+	requireNoError(t, service.SuspendMember("alice"), "suspending should succeed")
+	errSuspended := service.Approve("dave", true)
+
+	// Then: both are refused, and dave is not added
+	if ExitCode(errNoKey) != ExitKey {
+		t.Errorf("expected a key error without a member's key, got %v", errNoKey)
+	}
+	if errSuspended == nil || !containsString(errSuspended.Error(), "suspended") {
+		t.Errorf("expected suspended alice to be refused, got %v", errSuspended)
+	}
+	if loadManifestOrFail(t, service.configPath).FindMember("dave") != nil {
+		t.Error("expected dave not to be added")
+	}
+}
+
+func TestSopsManager_RejectJoinRequest(t *testing.T) {
+	t.Parallel()
+
+	// Given: a pending request from dave
+	service, _, _ := setupScopeTest(t)
+	path, err := service.saveJoinRequest(newTestJoinRequest(t))
+	requireNoError(t, err, "saving the request should succeed")
+
+	// When: rejecting it
+	requireNoError(t, service.Reject("dave"), "rejecting should succeed")
+
+	// Then: the request is gone and dave was not added
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected %s to be removed, got %v", path, err)
+	}
+	if loadManifestOrFail(t, service.configPath).FindMember("dave") != nil {
+		t.Error("expected dave not to be added")
+	}
+	requireError(t, service.Reject("dave"), "rejecting a missing request should fail")
+	requireError(t, service.Approve("../dave", true), "request IDs should be validated")
+}
+
+func TestSopsManager_JoinWritesSignedRequest(t *testing.T) {
+	// Not parallel: the fake age-keygen is put on PATH
+
+	// Given: dave, not a member yet, with an age key and an SSH key
+	service, output, tempDir := setupScopeTest(t)
+	useFakeAgeKeygen(t)
+	t.Setenv("SOPSISTRY_USER_ID", "dave")
+	service.secretsDir = filepath.Join(tempDir, ".secrets")
+	writeLocalKey(t, service.secretsDir, testRecipientD)
+	sshKey := newTestSSHKey(t)
+
+	// When: asking to join production
+	requireNoError(t, service.Join(sshKey, "production"), "joining should succeed")
+
+	// Then: the request is signed with dave's SSH key, and the fingerprint is shown
+	request, _, err := service.loadJoinRequest("dave")
+	requireNoError(t, err, "loading the request should succeed")
+	requireNoError(t, request.Verify(), "the written request should verify")
+	if request.AgeKey != testRecipientD || !containsString(request.SigningKey, "dave@laptop") {
+		t.Errorf("expected dave's age key and SSH key, got %+v", request)
+	}
+	if !containsString(output.String(), "Signed with SSH key SHA256:") {
+		t.Errorf("expected the SSH key fingerprint, got: %s", output.String())
+	}
+}
+
+func TestJoinRequest_VerifyDetectsChanges(t *testing.T) {
+	t.Parallel()
+
+	// Given: a signed request
+	request := newTestJoinRequest(t)
+	requireNoError(t, request.Verify(), "the signed request should verify")
+
+	// When: changing its age key, its scopes, or dropping the signature
+	changedKey := *request
+	changedKey.AgeKey = testRecipientC
+	changedScopes := *request
+	changedScopes.Scopes = []string{"production", "development"}
+	unsigned := *request
+	unsigned.Signature = ""
+
+	// Then: none of them verifies
+	requireError(t, changedKey.Verify(), "a changed age key should be detected")
+	requireError(t, changedScopes.Verify(), "changed scopes should be detected")
+	requireError(t, unsigned.Verify(), "unsigned requests should be refused")
+}
@@ -0,0 +1,224 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// JSONOutputVersion is the schema version of every --json document.
+// Bump it whenever a field is removed or changes meaning.
+const JSONOutputVersion = 1
+
+// JSONDocument is the envelope written by every command in --json mode.
+// Error is set when the command failed; Result is then only present if it
+// describes partial progress, e.g. which files apply rolled back.
+type JSONDocument struct {
+	Result  any        `json:"result,omitempty"`
+	Error   *JSONError `json:"error,omitempty"`
+	Command string     `json:"command"`
+	Version int        `json:"version"`
+}
+
+// JSONError describes a failed command
+type JSONError struct {
+	Message  string `json:"message"`
+	Category string `json:"category"` // SopsError.Category(), or "general" for untyped errors
+}
+
+// PlanResult is the --json result of 'sistry plan'
+type PlanResult struct {
+	Actions  []Action       `json:"actions"`
+	Orphans  []OrphanedFile `json:"orphans"`             // Encrypted files no scope matches
+	Ignored  []string       `json:"ignored,omitempty"`   // Files matching a scope that git ignores
+	PlanFile string         `json:"plan_file,omitempty"` // Set when the plan was saved with --out
+	Changes  int            `json:"changes"`
+	UpToDate int            `json:"up_to_date"`
+}
+
+// FileStatus is the outcome of one planned action during apply
+type FileStatus string
+
+// File statuses reported by apply
+const (
+	FileApplied    FileStatus = "applied"     // Action executed successfully
+	FileFailed     FileStatus = "failed"      // Action failed; all changes were rolled back
+	FileRolledBack FileStatus = "rolled-back" // Action succeeded but was undone after a later failure
+	FileNotRun     FileStatus = "not-run"     // Action was not attempted because an earlier one failed
+)
+
+// FileResult reports what apply did to a single file
+type FileResult struct {
+	File   string     `json:"file"`
+	Action ActionType `json:"action"`
+	Status FileStatus `json:"status"`
+	Error  string     `json:"error,omitempty"`
+}
+
+// ApplyResult is the --json result of 'sistry apply'
+type ApplyResult struct {
+	Files   []FileResult `json:"files"`
+	Applied int          `json:"applied"`
+}
+
+// KeyState classifies a member's key by age
+type KeyState string
+
+// Key states reported by check
+const (
+	KeyOK       KeyState = "ok"
+	KeyExpiring KeyState = "expiring"
+	KeyExpired  KeyState = "expired"
+)
+
+// KeyStatus reports the age of one of a member's keys
+type KeyStatus struct {
+	Created       time.Time `json:"created"`
+	Member        string    `json:"member"`
+	Label         string    `json:"label,omitempty"` // Device label; empty for the member's age_key
+	Machine       bool      `json:"machine"`         // Key of a machine member
+	Suspended     bool      `json:"suspended"`       // The member's access is suspended
+	State         KeyState  `json:"state"`
+	PrivateKey    string    `json:"private_key,omitempty"` // Local key file name; empty if no local key matches
+	AgeDays       int       `json:"age_days"`
+	DaysRemaining int       `json:"days_remaining"` // Negative once the key has expired
+}
+
+// ManagedFileStatus reports the encryption state of one file matched by a scope
+type ManagedFileStatus struct {
+	File          string          `json:"file"`
+	Scope         string          `json:"scope"`
+	State         EncryptionState `json:"state,omitempty"`
+	Error         string          `json:"error,omitempty"`
+	AgeRecipients int             `json:"age_recipients"`
+}
+
+// CheckResult is the --json result of 'sistry check'
+type CheckResult struct {
+	SOPSConfig *SOPSConfigInfo     `json:"sops_config"`
+	Keys       []KeyStatus         `json:"keys"`
+	Files      []ManagedFileStatus `json:"files"`
+	Orphans    []OrphanedFile      `json:"orphans"` // Encrypted files no scope matches
+	Grants     []AccessGrant       `json:"grants"`  // Time-limited scope members
+	Errors     []JSONError         `json:"errors"`  // Non-fatal failures while gathering the above
+}
+
+// DriftResult is the --json result of 'sistry check --drift', reported
+// under command "check-drift"
+type DriftResult struct {
+	Files   []DriftedFile `json:"files"`   // Managed files that do not match the manifest
+	Checked int           `json:"checked"` // Number of managed files compared
+}
+
+// MemberResult is the --json result of 'sistry add-member', 'sistry remove-member',
+// 'sistry member suspend|resume' and 'sistry reject'
+type MemberResult struct {
+	Member string   `json:"member"`
+	Change string   `json:"change"`           // "added", "removed", "suspended", "resumed", "approved" or "rejected"
+	Scopes []string `json:"scopes,omitempty"` // Scopes an added member joined
+}
+
+// MachineResult is the --json result of 'sistry member add-machine'. It is the
+// only time the private key is shown.
+type MachineResult struct {
+	MemberResult
+	AgeKey     string `json:"age_key"`
+	PrivateKey string `json:"private_key"`
+}
+
+// JoinResult is the --json result of 'sistry join'
+type JoinResult struct {
+	Request string `json:"request"` // Path of the request file
+	JoinRequest
+}
+
+// ApprovalResult is the --json result of 'sistry approve'
+type ApprovalResult struct {
+	MemberResult
+	ApprovedBy string     `json:"approved_by"`
+	Plan       PlanResult `json:"plan"` // The plan after adding the member
+}
+
+// KeyResult is the --json result of 'sistry key add'
+type KeyResult struct {
+	Member string `json:"member"`
+	Label  string `json:"label"`
+	AgeKey string `json:"age_key"`
+	Change string `json:"change"` // "added"
+}
+
+// ScopeResult is the --json result of the 'sistry scope' subcommands
+type ScopeResult struct {
+	Scope  string     `json:"scope"`
+	Change string     `json:"change"` // The subcommand, e.g. "add-member"
+	Plan   PlanResult `json:"plan"`   // The plan after the change
+}
+
+// ListResult is the --json result of 'sistry list': the manifest, with the
+// scopes each member can read and how
+type ListResult struct {
+	*Manifest
+	MemberScopes map[string][]ScopeAccess `json:"member_scopes"`
+}
+
+// ValidateResult is the --json result of 'sistry validate'
+type ValidateResult struct {
+	Problems []Diagnostic `json:"problems"`
+	Valid    bool         `json:"valid"`
+}
+
+// WriteJSONResult writes a successful command result as a JSON document
+func WriteJSONResult(w io.Writer, command string, result any) error {
+	return writeJSONDocument(w, JSONDocument{Version: JSONOutputVersion, Command: command, Result: result})
+}
+
+// WriteJSONError writes a failed command as a JSON document
+func WriteJSONError(w io.Writer, command string, err error) error {
+	return writeJSONDocument(w, JSONDocument{Version: JSONOutputVersion, Command: command, Error: NewJSONError(err)})
+}
+
+// WriteJSONFailure writes a failed command together with its partial result.
+// The returned error wraps err and is recognized by IsJSONReported.
+func WriteJSONFailure(w io.Writer, command string, result any, err error) error {
+	doc := JSONDocument{Version: JSONOutputVersion, Command: command, Result: result, Error: NewJSONError(err)}
+	if writeErr := writeJSONDocument(w, doc); writeErr != nil {
+		return err
+	}
+	return reportedError{err}
+}
+
+// reportedError marks an error whose JSON document has already been written
+type reportedError struct {
+	error
+}
+
+func (e reportedError) Unwrap() error {
+	return e.error
+}
+
+// IsJSONReported reports whether err was already written as a JSON document
+func IsJSONReported(err error) bool {
+	var reported reportedError
+	return errors.As(err, &reported)
+}
+
+// NewJSONError converts err into its JSON form, keeping the SopsError category when there is one
+func NewJSONError(err error) *JSONError {
+	category := "general"
+	var sopsErr SopsError
+	if errors.As(err, &sopsErr) {
+		category = sopsErr.Category()
+	}
+	return &JSONError{Message: err.Error(), Category: category}
+}
+
+func writeJSONDocument(w io.Writer, doc JSONDocument) error {
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON output: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
@@ -0,0 +1,92 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCheckReport_JSON(t *testing.T) {
+	t.Parallel()
+
+	// Given: a team with one fresh and one expired key, and one managed plaintext file
+	tempDir := t.TempDir()
+	file := writeTestFile(t, tempDir, "dev.env", "KEY=value\n")
+	now := time.Now().UTC()
+	manifest := createScopedManifest(tempDir)
+	manifest.Members = []Member{
+		{ID: "alice", AgeKey: testRecipientA, Created: now.AddDate(0, 0, -30)},
+		{ID: "bob", AgeKey: testRecipientB, Created: now.AddDate(0, 0, -200)},
+	}
+	manifest.Settings.MaxKeyAgeDays = 180
+	configPath := filepath.Join(tempDir, "sopsistry.yaml")
+	requireNoError(t, manifest.Save(configPath), "saving manifest should succeed")
+
+	var output bytes.Buffer
+	service := &SopsManager{sopsPath: "sops", configPath: configPath, secretsDir: ".secrets", reporter: NewWriterReporter(&output)}
+
+	// When: reporting check results as JSON
+	err := service.CheckReport(&SOPSConfigInfo{ConfigPath: ".sops.yaml"})
+
+	// Then: a versioned document lists key and file states, failing on bob's expired key
+	if ExitCode(err) != ExitKey || !IsJSONReported(err) {
+		t.Errorf("expected an already reported key error, got %v", err)
+	}
+	var doc struct {
+		Error   *JSONError  `json:"error"`
+		Result  CheckResult `json:"result"`
+		Command string      `json:"command"`
+		Version int         `json:"version"`
+	}
+	requireNoError(t, json.Unmarshal(output.Bytes(), &doc), "output should be valid JSON")
+	if doc.Version != JSONOutputVersion || doc.Command != "check" {
+		t.Errorf("unexpected envelope: version %d, command %q", doc.Version, doc.Command)
+	}
+	if doc.Error == nil || doc.Error.Category != "key" {
+		t.Errorf("expected a key error in the document, got %+v", doc.Error)
+	}
+	if len(doc.Result.Keys) != 2 {
+		t.Fatalf("expected 2 key statuses, got %d", len(doc.Result.Keys))
+	}
+	if got := doc.Result.Keys[0]; got.State != KeyOK || got.AgeDays != 30 || got.DaysRemaining <= 0 {
+		t.Errorf("expected alice ok with days remaining, got %+v", got)
+	}
+	if got := doc.Result.Keys[1]; got.State != KeyExpired || got.DaysRemaining >= 0 {
+		t.Errorf("expected bob expired with negative days remaining, got %+v", got)
+	}
+	if len(doc.Result.Files) != 1 || doc.Result.Files[0].File != file || doc.Result.Files[0].State != StatePlaintext {
+		t.Errorf("expected plaintext dev.env, got %+v", doc.Result.Files)
+	}
+}
+
+func TestWriteJSONError_Category(t *testing.T) {
+	t.Parallel()
+
+	// Given: a typed error wrapped in further context
+	err := fmt.Errorf("rotation failed: %w", NewKeyError("rotate", "alice", errors.New("expired")))
+
+	// When: writing it as a JSON document
+	var output bytes.Buffer
+	requireNoError(t, WriteJSONError(&output, "rotate-key", err), "writing error should succeed")
+
+	// Then: the SopsError category is preserved
+	var doc JSONDocument
+	requireNoError(t, json.Unmarshal(output.Bytes(), &doc), "output should be valid JSON")
+	if doc.Error == nil || doc.Error.Category != "key" {
+		t.Errorf("expected key category, got %+v", doc.Error)
+	}
+
+	// When: the error is untyped
+	output.Reset()
+	requireNoError(t, WriteJSONError(&output, "plan", errors.New("boom")), "writing error should succeed")
+
+	// Then: it is reported as general
+	requireNoError(t, json.Unmarshal(output.Bytes(), &doc), "output should be valid JSON")
+	if doc.Error.Category != "general" {
+		t.Errorf("expected general category, got %q", doc.Error.Category)
+	}
+}
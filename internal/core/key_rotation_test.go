@@ -2,6 +2,7 @@ package core
 
 import (
 	"bytes"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -22,7 +23,7 @@ func TestKeyRotation_ValidationLogic(t *testing.T) {
 		Members: []Member{
 			{
 				ID:      "testuser",
-				AgeKey:  "age1testkey12345...",
+				AgeKey:  testRecipientA,
 				Created: createdTime,
 			},
 		},
@@ -49,19 +50,19 @@ func TestKeyRotation_ValidationLogic(t *testing.T) {
 		sopsPath:   "/nonexistent/sops", // Will fail, but we test validation first
 		configPath: configPath,
 		secretsDir: secretsDir,
-		output:     &output,
+		reporter:   NewWriterReporter(&output),
 	}
 
 	// Test key expiry check logic directly
 	member := &manifest.Members[0]
-	err := service.checkKeyExpiry(member, 180)
+	err := service.checkKeyExpiry(member.ID, member.AgeKeys()[0], 180)
 	if err != nil {
 		t.Errorf("Fresh key should not be expired: %v", err)
 	}
 
 	// Test expired key
 	member.Created = time.Now().UTC().AddDate(0, 0, -200) // 200 days ago
-	err = service.checkKeyExpiry(member, 180)
+	err = service.checkKeyExpiry(member.ID, member.AgeKeys()[0], 180)
 	if err == nil {
 		t.Error("Expired key should return error")
 	}
@@ -91,7 +92,7 @@ func TestKeyRotation_ExpiredKey(t *testing.T) {
 		Members: []Member{
 			{
 				ID:      currentUser, // Use actual current user
-				AgeKey:  "age1expiredkey...",
+				AgeKey:  testRecipientA,
 				Created: expiredTime,
 			},
 		},
@@ -115,7 +116,7 @@ func TestKeyRotation_ExpiredKey(t *testing.T) {
 		sopsPath:   "echo",
 		configPath: configPath,
 		secretsDir: secretsDir,
-		output:     &output,
+		reporter:   NewWriterReporter(&output),
 	}
 
 	// Test rotation without force - should fail
@@ -154,17 +155,17 @@ func TestCheckKeyExpiry_Warnings(t *testing.T) {
 		Members: []Member{
 			{
 				ID:      "fresh-user",
-				AgeKey:  "age1fresh...",
+				AgeKey:  testRecipientA,
 				Created: now.AddDate(0, 0, -30), // 30 days old - fresh
 			},
 			{
 				ID:      "warning-user",
-				AgeKey:  "age1warning...",
+				AgeKey:  testRecipientB,
 				Created: now.AddDate(0, 0, -170), // 170 days old - warning (expires in 10 days)
 			},
 			{
 				ID:      "expired-user",
-				AgeKey:  "age1expired...",
+				AgeKey:  testRecipientC,
 				Created: now.AddDate(0, 0, -200), // 200 days old - expired
 			},
 		},
@@ -183,13 +184,14 @@ func TestCheckKeyExpiry_Warnings(t *testing.T) {
 		sopsPath:   "sops",
 		configPath: configPath,
 		secretsDir: ".secrets",
-		output:     &output,
+		reporter:   NewWriterReporter(&output),
 	}
 
-	// Check key expiry
-	err := service.CheckKeyExpiry(false)
-	if err != nil {
-		t.Fatalf("CheckKeyExpiry failed: %v", err)
+	// Check key expiry: the expired key fails the check with a key error
+	err := service.CheckKeyExpiry()
+	var keyErr *KeyError
+	if !errors.As(err, &keyErr) || keyErr.KeyID != "expired-user" || ExitCode(err) != ExitKey {
+		t.Fatalf("Expected a key error for expired-user, got: %v", err)
 	}
 
 	outputStr := output.String()
@@ -231,7 +233,7 @@ func TestKeyRotation_UserNotFound(t *testing.T) {
 		Members: []Member{
 			{
 				ID:      "someoneelse",
-				AgeKey:  "age1someoneelse...",
+				AgeKey:  testRecipientA,
 				Created: time.Now().UTC(),
 			},
 		},
@@ -265,7 +267,7 @@ func TestKeyRotation_UserNotFound(t *testing.T) {
 		sopsPath:   "sops",
 		configPath: configPath,
 		secretsDir: secretsDir,
-		output:     &output,
+		reporter:   NewWriterReporter(&output),
 	}
 
 	// Attempt rotation - should fail with user not found
@@ -0,0 +1,114 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MemberType distinguishes people from CI runners, deploy bots and other
+// service accounts
+type MemberType string
+
+// Member types; an empty type means human
+const (
+	MemberHuman   MemberType = "human"
+	MemberMachine MemberType = "machine"
+)
+
+// ParseMemberType validates a member's type setting
+func ParseMemberType(value string) (MemberType, error) {
+	switch t := MemberType(value); t {
+	case "", MemberHuman:
+		return MemberHuman, nil
+	case MemberMachine:
+		return t, nil
+	default:
+		return "", fmt.Errorf("unknown member type %q (valid: human, machine)", value)
+	}
+}
+
+// IsMachine reports whether the member is a service account rather than a person.
+// Machine members only read the scopes that list them, directly or through a
+// group: they never join default_scopes and are not passed on by
+// inherits_members_from.
+func (m Member) IsMachine() bool {
+	return m.Type == MemberMachine
+}
+
+// maxKeyAgeDays returns how long the member's keys stay valid. Human keys
+// live at least DefaultMaxKeyAgeDays; machine keys use their own setting.
+func (s Settings) maxKeyAgeDays(member Member) int {
+	if member.IsMachine() {
+		if s.MaxMachineKeyAgeDays > 0 {
+			return s.MaxMachineKeyAgeDays
+		}
+		return DefaultMaxMachineKeyAgeDays
+	}
+	return max(s.MaxKeyAgeDays, DefaultMaxKeyAgeDays) // ensure minimum of 180 days (6 months)
+}
+
+// isMachine reports whether id names a machine member
+func (m *Manifest) isMachine(id string) bool {
+	member := m.FindMember(id)
+	return member != nil && member.IsMachine()
+}
+
+// AddMachine adds a machine member to the given scopes with a freshly
+// generated key pair. The private key is not stored; it is printed once, to
+// be pasted into the CI system's secret store.
+func (s *SopsManager) AddMachine(id string, scopes []string) error {
+	manifest, err := LoadManifest(s.configPath)
+	if err != nil {
+		return fmt.Errorf(FailedToLoadManifestMsg, err)
+	}
+	if err := validateNewMachine(manifest, id, scopes); err != nil {
+		return err
+	}
+
+	publicKey, privateKey, err := runAgeKeygen()
+	if err != nil {
+		return NewKeyError("generate", id, err)
+	}
+
+	member := Member{ID: id, Type: MemberMachine, AgeKey: publicKey, Created: time.Now().UTC()}
+	if err := s.saveNewMember(member, scopes); err != nil {
+		return err
+	}
+
+	if s.jsonOutput {
+		return WriteJSONResult(s.reporter.Out(), "member add-machine", MachineResult{
+			MemberResult: MemberResult{Member: id, Change: "added", Scopes: scopes},
+			AgeKey:       publicKey,
+			PrivateKey:   privateKey,
+		})
+	}
+
+	s.reporter.Infof("Added machine member %s (scopes: %s)", id, strings.Join(scopes, ", "))
+	s.reporter.Infof("Public key: %s", publicKey)
+	s.reporter.Warnf("Store this private key in your CI secret store (e.g. as SOPS_AGE_KEY); it is not saved and will not be shown again:")
+	_, _ = fmt.Fprintln(s.reporter.Out(), privateKey) // Shown even in quiet mode
+	s.reporter.Infof("Run 'sistry plan' to see changes, then 'sistry apply' to re-encrypt files")
+	return nil
+}
+
+// validateNewMachine checks that a machine member can be added to scopes,
+// which must be given explicitly
+func validateNewMachine(manifest *Manifest, id string, scopes []string) error {
+	if _, err := NewMemberID(id); err != nil {
+		return NewManifestError("validate", "member "+id, err)
+	}
+	if manifest.FindMember(id) != nil {
+		return fmt.Errorf("member %s already exists", id)
+	}
+	if len(scopes) == 0 {
+		return NewManifestError("validate", "member "+id,
+			fmt.Errorf("machine members never join default_scopes: name their scopes with --scope"))
+	}
+	for _, scope := range scopes {
+		if manifest.FindScope(scope) == nil {
+			return NewManifestError("validate", "member "+id, fmt.Errorf("scope %s not found", scope))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package core
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// createMachineManifest returns a hierarchical manifest where a CI runner
+// reads production
+func createMachineManifest(dir string) *Manifest {
+	manifest := createHierarchicalManifest(dir)
+	manifest.Members = append(manifest.Members, Member{ID: "ci-prod", Type: MemberMachine, AgeKey: testRecipientD})
+	manifest.Scopes[0].Members = append(manifest.Scopes[0].Members, "ci-prod")
+	return manifest
+}
+
+func TestManifest_MachineMembersAreNotInherited(t *testing.T) {
+	t.Parallel()
+
+	// Given: staging inherits production's readers, including its CI runner
+	manifest := createMachineManifest(t.TempDir())
+
+	// When: resolving both scopes
+	production, err := manifest.GetScopeMembers("production")
+	requireNoError(t, err, "resolving production should succeed")
+	staging, err := manifest.GetScopeMembers("staging")
+	requireNoError(t, err, "resolving staging should succeed")
+
+	// Then: the runner reads only the scope listing it
+	if !slices.Equal(MapSlice(production, func(m Member) string { return m.ID }), []string{"alice", "ci-prod"}) {
+		t.Errorf("expected alice and ci-prod in production, got %v", production)
+	}
+	if !slices.Equal(MapSlice(staging, func(m Member) string { return m.ID }), []string{"bob", "alice"}) {
+		t.Errorf("expected bob and alice in staging, got %v", staging)
+	}
+}
+
+func TestSettings_MachineKeyAge(t *testing.T) {
+	t.Parallel()
+
+	human := Member{ID: "alice"}
+	machine := Member{ID: "ci-prod", Type: MemberMachine}
+
+	for _, tc := range []struct {
+		name     string
+		settings Settings
+		member   Member
+		want     int
+	}{
+		{"human default", Settings{}, human, DefaultMaxKeyAgeDays},
+		{"human below minimum", Settings{MaxKeyAgeDays: 30}, human, DefaultMaxKeyAgeDays},
+		{"machine default", Settings{MaxKeyAgeDays: 365}, machine, DefaultMaxMachineKeyAgeDays},
+		{"machine setting", Settings{MaxMachineKeyAgeDays: 30}, machine, 30},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tc.settings.maxKeyAgeDays(tc.member); got != tc.want {
+				t.Errorf("expected %d days, got %d", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestSopsManager_MachineKeysCheckedSeparately(t *testing.T) {
+	t.Parallel()
+
+	// Given: a machine key and a human key, both 100 days old
+	service, output, _ := setupScopeTest(t)
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	requireNoError(t, service.editManifest(func(editor *ManifestEditor) error {
+		if err := editor.AddMember(Member{ID: "ci-prod", Type: MemberMachine, AgeKey: testRecipientD, Created: now.AddDate(0, 0, -100)}); err != nil {
+			return err
+		}
+		return editor.SetMemberKey("alice", testRecipientA, now.AddDate(0, 0, -100))
+	}), "editing manifest should succeed")
+
+	// When: classifying keys and listing the team
+	statuses, err := service.keyStatuses(false, now)
+	requireNoError(t, err, "key statuses should succeed")
+	requireNoError(t, service.List(false), "list should succeed")
+
+	// Then: only the machine key has expired, and machines come last
+	last := statuses[len(statuses)-1]
+	if last.Member != "ci-prod" || !last.Machine || last.State != KeyExpired {
+		t.Errorf("expected ci-prod's key last and expired, got %+v", last)
+	}
+	if statuses[0].Member != "alice" || statuses[0].State != KeyOK {
+		t.Errorf("expected alice's key to be valid, got %+v", statuses[0])
+	}
+	if !containsString(output.String(), "Machine Members:\n  ci-prod: ") {
+		t.Errorf("expected a machine members section, got: %s", output.String())
+	}
+}
+
+func TestValidateNewMachine(t *testing.T) {
+	t.Parallel()
+
+	manifest := createScopedManifest(t.TempDir())
+
+	requireNoError(t, validateNewMachine(manifest, "ci-prod", []string{"production"}), "listed scopes should be accepted")
+	requireError(t, validateNewMachine(manifest, "ci-prod", nil), "default_scopes should not be used for machines")
+	requireError(t, validateNewMachine(manifest, "ci-prod", []string{"staging"}), "unknown scopes should be refused")
+	requireError(t, validateNewMachine(manifest, "alice", []string{"production"}), "existing members should be refused")
+}
+
+func TestLoadManifest_ValidatesMemberType(t *testing.T) {
+	t.Parallel()
+
+	// Given: a member with an unknown type
+	path := writeTestFile(t, t.TempDir(), "sopsistry.yaml", `members:
+  - id: deploy-bot
+    type: robot
+    age_key: `+testRecipientA+`
+scopes:
+  - name: default
+    patterns: ["*.env"]
+    members: [deploy-bot]
+`)
+
+	// When: loading it
+	_, err := LoadManifest(path)
+
+	// Then: the type is reported
+	requireError(t, err, "unknown member types should be refused")
+	if !containsString(err.Error(), `3:11: member deploy-bot: unknown member type "robot"`) {
+		t.Errorf("expected member type error, got: %v", err)
+	}
+}
+
+func TestSopsManager_SaveMachineMember(t *testing.T) {
+	t.Parallel()
+
+	// Given: a manifest file
+	service, _, _ := setupScopeTest(t)
+
+	// When: adding a machine member
+	err := service.saveNewMember(Member{ID: "ci-prod", Type: MemberMachine, AgeKey: testRecipientD, Created: time.Now().UTC()}, []string{"production"})
+
+	// Then: its type is saved
+	requireNoError(t, err, "adding member should succeed")
+	manifest := loadManifestOrFail(t, service.configPath)
+	if member := manifest.FindMember("ci-prod"); member == nil || !member.IsMachine() {
+		t.Errorf("expected ci-prod to be a machine member, got %+v", member)
+	}
+}
@@ -3,11 +3,11 @@ package core
 import (
 	"crypto/sha1" //nolint:gosec // SHA-1 used for non-cryptographic filename hashing only
 	"fmt"
-	"io"
 	"os"
 	"os/user"
 	"path/filepath"
 	"slices"
+	"strings"
 	"time"
 )
 
@@ -16,7 +16,8 @@ type SopsManager struct { //nolint:govet // Field alignment optimization not cri
 	sopsPath   string
 	configPath string
 	secretsDir string
-	output     io.Writer
+	reporter   Reporter
+	jsonOutput bool
 }
 
 const warningThresholdHours = 14 * 24 * time.Hour
@@ -27,10 +28,22 @@ func NewSopsManager(sopsPath string) *SopsManager {
 		sopsPath:   sopsPath,
 		configPath: "sopsistry.yaml",
 		secretsDir: ".secrets",
-		output:     os.Stdout,
+		reporter:   NewConsoleReporter(ReporterOptions{Level: LevelNormal}),
 	}
 }
 
+// WithReporter sets where all messages of this manager and the operations it runs go
+func (s *SopsManager) WithReporter(reporter Reporter) *SopsManager {
+	s.reporter = reporter
+	return s
+}
+
+// WithJSONOutput makes commands emit a single versioned JSON document instead of text
+func (s *SopsManager) WithJSONOutput(jsonOutput bool) *SopsManager { //nolint:revive // jsonOutput is a legitimate CLI flag parameter
+	s.jsonOutput = jsonOutput
+	return s
+}
+
 // Init initializes a new SOPS team configuration
 func (s *SopsManager) Init(force bool) error {
 	if err := s.checkInitialization(force); err != nil {
@@ -42,12 +55,12 @@ func (s *SopsManager) Init(force bool) error {
 		return err
 	}
 
-	publicKey, err := s.setupAgeKey()
+	memberID, err := s.getCurrentMemberID()
 	if err != nil {
 		return err
 	}
 
-	memberID, err := s.getCurrentMemberID()
+	publicKey, err := s.setupAgeKey(nil, memberID)
 	if err != nil {
 		return err
 	}
@@ -104,15 +117,17 @@ func (s *SopsManager) setupEnvironment() (bool, error) {
 	return secretsDirExisted, nil
 }
 
-func (s *SopsManager) setupAgeKey() (string, error) {
+// setupAgeKey returns the public key of memberID's local age key, generating
+// one when there is none. manifest may be nil before the team is initialized.
+func (s *SopsManager) setupAgeKey(manifest *Manifest, memberID string) (string, error) {
 	// Check for existing keys using pattern
-	existingKey, publicKey, err := s.findExistingKey()
+	existingKey, publicKey, err := s.findExistingKey(manifest, memberID)
 	if err != nil {
 		return "", err
 	}
 
 	if existingKey != "" {
-		_, _ = fmt.Fprintf(s.output, "Using existing age key at %s\n", existingKey)
+		s.reporter.Infof("Using existing age key at %s", existingKey)
 		return publicKey, nil
 	}
 
@@ -120,27 +135,66 @@ func (s *SopsManager) setupAgeKey() (string, error) {
 	return s.generateNewAgeKey()
 }
 
-// findExistingKey looks for any existing key file and returns path + public key
-func (s *SopsManager) findExistingKey() (keyPath, publicKey string, err error) {
+// localKey is a private key file in the secrets directory
+type localKey struct {
+	Path      string
+	PublicKey string
+}
+
+// localKeys returns every private key in the secrets directory with its public key
+func (s *SopsManager) localKeys() ([]localKey, error) {
 	pattern := filepath.Join(s.secretsDir, "key-*.txt")
 	matches, err := filepath.Glob(pattern)
 	if err != nil {
-		err = fmt.Errorf("failed to search for existing keys: %w", err)
-		return
+		return nil, fmt.Errorf("failed to search for existing keys: %w", err)
 	}
 
-	if len(matches) == 0 {
-		return // No existing keys
+	keys := make([]localKey, 0, len(matches))
+	for _, keyPath := range matches {
+		publicKey, err := s.getPublicKeyFromPrivateKey(keyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract public key from %s: %w", keyPath, err)
+		}
+		keys = append(keys, localKey{Path: keyPath, PublicKey: publicKey})
 	}
+	return keys, nil
+}
 
-	// Use first key found (in practice should be only one for current user)
-	keyPath = matches[0]
-	publicKey, err = s.getPublicKeyFromPrivateKey(keyPath)
-	if err != nil {
-		err = fmt.Errorf("failed to extract public key from %s: %w", keyPath, err)
-		return
+// findExistingKey returns the local private key to use for memberID, or an
+// empty path when there is none. With a manifest, a key registered for the
+// member is preferred and keys of other members are never used; several
+// candidates are an error rather than a guess.
+func (s *SopsManager) findExistingKey(manifest *Manifest, memberID string) (keyPath, publicKey string, err error) {
+	keys, err := s.localKeys()
+	if err != nil || len(keys) == 0 {
+		return "", "", err
+	}
+
+	candidates := keys
+	if manifest != nil {
+		candidates = Filter(keys, func(k localKey) bool { return len(manifest.MemberIDsForKey(k.PublicKey)) == 0 })
+		if member := manifest.FindMember(memberID); member != nil {
+			if own := Filter(keys, func(k localKey) bool { return slices.Contains(member.PublicKeys(), k.PublicKey) }); len(own) > 0 {
+				candidates = own
+			}
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return "", "", nil
+	case 1:
+		return candidates[0].Path, candidates[0].PublicKey, nil
+	default:
+		return "", "", s.ambiguousKeysError(memberID, candidates)
 	}
-	return
+}
+
+// ambiguousKeysError reports that several local keys could belong to memberID
+func (s *SopsManager) ambiguousKeysError(memberID string, keys []localKey) error {
+	paths := MapSlice(keys, func(k localKey) string { return filepath.Base(k.Path) })
+	return NewKeyError("find", memberID, fmt.Errorf("several private keys in %s could be yours (%s); remove the ones no longer in use",
+		s.secretsDir, strings.Join(paths, ", ")))
 }
 
 // findKeyForPublicKey searches for the private key file that corresponds to the given public key
@@ -162,7 +216,7 @@ func (s *SopsManager) findKeyForPublicKey(targetPublicKey string) (string, error
 		}
 	}
 
-	return "", fmt.Errorf("no private key found for public key %s", targetPublicKey)
+	return "", NewKeyError("find", shortenKey(targetPublicKey), fmt.Errorf("no private key in %s matches public key %s", s.secretsDir, targetPublicKey))
 }
 
 // generateNewAgeKey creates a new age key with private-key-based naming
@@ -226,6 +280,7 @@ func (s *SopsManager) createInitialManifest(memberID, publicKey string, created
 				Members:  []string{memberID},
 			},
 		},
+		DefaultScopes: []string{"default"},
 		Settings: Settings{
 			SopsVersion:   "3.8.0",
 			MaxKeyAgeDays: DefaultMaxKeyAgeDays, // default 6 months
@@ -235,129 +290,232 @@ func (s *SopsManager) createInitialManifest(memberID, publicKey string, created
 
 func (s *SopsManager) printInitializationSuccess(force bool, memberID, publicKey string, secretsDirExisted bool) { //nolint:revive // force is a legitimate CLI flag parameter
 	if force {
-		_, _ = fmt.Fprintf(s.output, "Re-initialized SOPS team configuration (force mode)\n")
+		s.reporter.Infof("Re-initialized SOPS team configuration (force mode)")
 	} else {
-		_, _ = fmt.Fprintf(s.output, "Initialized SOPS team configuration\n")
+		s.reporter.Infof("Initialized SOPS team configuration")
 	}
-	_, _ = fmt.Fprintf(s.output, "📄  Created %s\n", s.configPath)
+	s.reporter.Infof("📄  Created %s", s.configPath)
 
 	if secretsDirExisted {
-		_, _ = fmt.Fprintf(s.output, "🔒  Using existing %s directory\n", s.secretsDir)
+		s.reporter.Infof("🔒  Using existing %s directory", s.secretsDir)
 	} else {
-		_, _ = fmt.Fprintf(s.output, "🔒  Created %s directory\n", s.secretsDir)
+		s.reporter.Infof("🔒  Created %s directory", s.secretsDir)
 	}
 
 	// Show the final key name (safe to display as it's derived from private key)
 	if keyPath, err := s.findKeyForPublicKey(publicKey); err == nil {
-		_, _ = fmt.Fprintf(s.output, "🗝️   Age key: %s\n", filepath.Base(keyPath))
+		s.reporter.Infof("🗝️   Age key: %s", filepath.Base(keyPath))
 	}
-	_, _ = fmt.Fprintf(s.output, "🧑‍💻  Added %s as team member\n", memberID)
+	s.reporter.Infof("🧑‍💻  Added %s as team member", memberID)
 }
 
 func (s *SopsManager) showSOPSCoexistenceAdvice() {
 	detector := NewSOPSDetector()
 	sopsInfo, err := detector.DetectSOPSConfig()
 	if err == nil && sopsInfo.Exists {
-		_, _ = fmt.Fprintf(s.output, "\n%s\n", sopsInfo.GetCoexistenceAdvice())
+		s.reporter.Infof("\n%s", sopsInfo.GetCoexistenceAdvice())
 	}
 }
 
 func (s *SopsManager) printNextSteps() {
-	_, _ = fmt.Fprintf(s.output, "\n🚀 Next steps:\n")
-	_, _ = fmt.Fprintf(s.output, "1. Encrypt files: sistry encrypt <file> or sistry encrypt --[i]regex '^(password|key)' <file>\n")
-	_, _ = fmt.Fprintf(s.output, "2. Add more team members: sistry add-member <id> --key <age-pubkey>\n")
-	_, _ = fmt.Fprintf(s.output, "3. Review planned changes: sistry plan\n")
-	_, _ = fmt.Fprintf(s.output, "4. Apply changes: sistry apply\n")
+	s.reporter.Infof("\n🚀 Next steps:")
+	s.reporter.Infof("1. Encrypt files: sistry encrypt <file> or sistry encrypt --[i]regex '^(password|key)' <file>")
+	s.reporter.Infof("2. Add more team members: sistry add-member <id> --key <age-pubkey>")
+	s.reporter.Infof("3. Review planned changes: sistry plan")
+	s.reporter.Infof("4. Apply changes: sistry apply")
+}
+
+// PlanOptions controls how a plan is computed and displayed
+type PlanOptions struct {
+	OutFile          string // Save the plan here for a later 'apply <planfile>'
+	RotateDataKeys   bool
+	DetailedExitCode bool // Return ErrPlanHasChanges when the plan would change files
+}
+
+// ApplyOptions controls how planned changes are applied
+type ApplyOptions struct {
+	PlanFile string // Apply this saved plan instead of computing a new one
+	PlanOptions
+	RequireCleanGit  bool
+	SkipConfirmation bool
 }
 
 // Plan shows what changes would be made
-func (s *SopsManager) Plan(noColor bool) error {
-	manifest, err := LoadManifest(s.configPath)
+func (s *SopsManager) Plan(opts PlanOptions) error {
+	plan, err := s.computePlan(opts.RotateDataKeys)
 	if err != nil {
-		return fmt.Errorf(FailedToLoadManifestMsg, err)
+		return err
 	}
 
-	planner := NewPlanner(s.sopsPath)
-	plan, err := planner.ComputePlan(manifest)
-	if err != nil {
-		return fmt.Errorf("failed to compute plan: %w", err)
+	if opts.OutFile != "" {
+		saved, err := NewSavedPlan(plan, s.configPath)
+		if err != nil {
+			return err
+		}
+		if err := saved.Save(opts.OutFile); err != nil {
+			return err
+		}
 	}
 
-	plan.Display(noColor)
+	if s.jsonOutput {
+		if err := WriteJSONResult(s.reporter.Out(), "plan", plan.Result(opts.OutFile)); err != nil {
+			return err
+		}
+	} else {
+		plan.Display(s.reporter)
+		if opts.OutFile != "" {
+			s.reporter.Infof("\nSaved plan to %s", opts.OutFile)
+			s.reporter.Infof("To apply exactly this plan, run: sistry apply %s", opts.OutFile)
+		}
+	}
+
+	if opts.DetailedExitCode && plan.HasChanges() {
+		return ErrPlanHasChanges
+	}
 	return nil
 }
 
 // Apply executes planned changes
-func (s *SopsManager) Apply(requireCleanGit, skipConfirmation bool) error { //nolint:revive // CLI flag parameters are legitimate
-	if requireCleanGit {
+func (s *SopsManager) Apply(opts ApplyOptions) error {
+	if opts.RequireCleanGit {
 		if err := s.checkGitClean(); err != nil {
 			return err
 		}
 	}
 
-	manifest, err := LoadManifest(s.configPath)
+	plan, err := s.planToApply(opts)
 	if err != nil {
-		return fmt.Errorf(FailedToLoadManifestMsg, err)
+		return err
 	}
 
-	planner := NewPlanner(s.sopsPath)
-	plan, err := planner.ComputePlan(manifest)
-	if err != nil {
-		return fmt.Errorf("failed to compute plan: %w", err)
+	if !plan.HasChanges() {
+		if s.jsonOutput {
+			return WriteJSONResult(s.reporter.Out(), "apply", newApplyResult(plan))
+		}
+		s.reporter.Infof("No changes to apply")
+		return nil
 	}
 
-	if len(plan.Actions) == 0 {
-		_, _ = fmt.Fprintln(s.output, "No changes to apply")
-		return nil
+	if s.jsonOutput {
+		if !opts.SkipConfirmation {
+			return fmt.Errorf("--json cannot prompt for confirmation; pass --yes to apply")
+		}
+		result, err := NewExecutor(s.sopsPath).WithReporter(s.reporter).Execute(plan)
+		if err != nil {
+			return WriteJSONFailure(s.reporter.Out(), "apply", result, err)
+		}
+		return WriteJSONResult(s.reporter.Out(), "apply", result)
 	}
 
-	if !skipConfirmation {
-		plan.Display(false)
-		fmt.Print("\nApply these changes? [y/N]: ")
+	if !opts.SkipConfirmation {
+		plan.Display(s.reporter)
+		_, _ = fmt.Fprint(s.reporter.Out(), "\nApply these changes? [y/N]: ")
 		var response string
 		_, _ = fmt.Scanln(&response) // User input, ignore errors
 		if response != "y" && response != "Y" {
-			_, _ = fmt.Fprintln(s.output, "Cancelled")
-			return nil
+			s.reporter.Infof("Cancelled")
+			return ErrCancelled
 		}
 	}
 
-	executor := NewExecutor(s.sopsPath)
-	return executor.Execute(plan)
+	executor := NewExecutor(s.sopsPath).WithReporter(s.reporter)
+	_, err = executor.Execute(plan)
+	return err
+}
+
+func (s *SopsManager) computePlan(rotateDataKeys bool) (*Plan, error) { //nolint:revive // rotateDataKeys is a legitimate CLI flag parameter
+	manifest, err := LoadManifest(s.configPath)
+	if err != nil {
+		return nil, fmt.Errorf(FailedToLoadManifestMsg, err)
+	}
+
+	planner := NewPlanner(s.sopsPath).WithRotateDataKeys(rotateDataKeys)
+	plan, err := planner.ComputePlan(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute plan: %w", err)
+	}
+	return plan, nil
+}
+
+// planToApply loads the saved plan when one is given, refusing it if stale,
+// and computes a fresh plan otherwise
+func (s *SopsManager) planToApply(opts ApplyOptions) (*Plan, error) {
+	if opts.PlanFile == "" {
+		return s.computePlan(opts.RotateDataKeys)
+	}
+
+	if opts.RotateDataKeys {
+		return nil, fmt.Errorf("--rotate-data-keys cannot be combined with a saved plan; pass it to 'sistry plan' instead")
+	}
+
+	saved, err := LoadSavedPlan(opts.PlanFile)
+	if err != nil {
+		return nil, err
+	}
+	if err := saved.CheckStale(s.configPath); err != nil {
+		return nil, fmt.Errorf("saved plan %s is stale: %w", opts.PlanFile, err)
+	}
+	return saved.Plan, nil
 }
 
-// AddMember adds a new team member
-func (s *SopsManager) AddMember(id, ageKey string) error {
+// AddMember adds a new team member to the given scopes, or to the
+// manifest's default scopes when none are given
+func (s *SopsManager) AddMember(id, ageKey string, scopes ...string) error {
 	manifest, err := LoadManifest(s.configPath)
 	if err != nil {
 		return fmt.Errorf(FailedToLoadManifestMsg, err)
 	}
 
+	if _, err := NewMemberID(id); err != nil {
+		return NewManifestError("validate", "member "+id, err)
+	}
+	if _, err := NewAgePublicKey(ageKey); err != nil {
+		return NewManifestError("validate", "member "+id, err)
+	}
+
 	// Extract member IDs for efficient lookup
 	memberIDs := MapSlice(manifest.Members, func(m Member) string { return m.ID })
 	if slices.Contains(memberIDs, id) {
 		return fmt.Errorf("member %s already exists", id)
 	}
 
-	manifest.Members = append(manifest.Members, Member{
-		ID:      id,
-		AgeKey:  ageKey,
-		Created: time.Now().UTC(),
-	})
+	if len(scopes) == 0 {
+		scopes = manifest.MemberDefaultScopes()
+	}
+	if len(scopes) == 0 {
+		return NewManifestError("validate", "member "+id,
+			fmt.Errorf("no scope to add the member to: use --scope or set default_scopes in %s", s.configPath))
+	}
 
-	for i := range manifest.Scopes {
-		if manifest.Scopes[i].Name == "default" {
-			manifest.Scopes[i].Members = append(manifest.Scopes[i].Members, id)
-			break
-		}
+	if err := s.saveNewMember(Member{ID: id, AgeKey: ageKey, Created: time.Now().UTC()}, scopes); err != nil {
+		return err
 	}
 
-	if err := manifest.Save(s.configPath); err != nil {
-		return fmt.Errorf("failed to save manifest: %w", err)
+	if s.jsonOutput {
+		return WriteJSONResult(s.reporter.Out(), "add-member", MemberResult{Member: id, Change: "added", Scopes: scopes})
 	}
 
-	_, _ = fmt.Fprintf(s.output, "Added member %s to team\n", id)
-	_, _ = fmt.Fprintln(s.output, "Run 'sistry plan' to see changes, then 'sistry apply' to re-encrypt files")
+	s.reporter.Infof("Added member %s to team (scopes: %s)", id, strings.Join(scopes, ", "))
+	s.reporter.Infof("Run 'sistry plan' to see changes, then 'sistry apply' to re-encrypt files")
+	return nil
+}
+
+// saveNewMember adds member to the manifest and to each of scopes
+func (s *SopsManager) saveNewMember(member Member, scopes []string) error {
+	err := s.editManifest(func(editor *ManifestEditor) error {
+		if err := editor.AddMember(member); err != nil {
+			return err
+		}
+		for _, scope := range scopes {
+			if err := editor.AddScopeMember(scope, member.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save manifest: %w", err)
+	}
 	return nil
 }
 
@@ -368,91 +526,307 @@ func (s *SopsManager) RemoveMember(id string) error {
 		return fmt.Errorf(FailedToLoadManifestMsg, err)
 	}
 
-	if err := s.removeMemberFromManifest(manifest, id); err != nil {
-		return err
+	if _, found := manifest.GetMemberAgeKey(id); !found {
+		return fmt.Errorf("member %s not found", id)
 	}
 
-	s.removeMemberFromAllScopes(manifest, id)
-
-	if err := manifest.Save(s.configPath); err != nil {
+	if err := s.editManifest(func(editor *ManifestEditor) error { return editor.RemoveMember(id) }); err != nil {
 		return fmt.Errorf("failed to save manifest: %w", err)
 	}
 
+	if s.jsonOutput {
+		return WriteJSONResult(s.reporter.Out(), "remove-member", MemberResult{Member: id, Change: "removed"})
+	}
+
 	s.printRemovalSuccess(id)
 	return nil
 }
 
-func (s *SopsManager) removeMemberFromManifest(manifest *Manifest, id string) error {
-	filtered := Filter(manifest.Members, func(m Member) bool { return m.ID != id })
-	if len(filtered) == len(manifest.Members) {
-		return fmt.Errorf("member %s not found", id)
+// editManifest applies edit to the manifest file, rewriting only the changed
+// YAML nodes so that comments and ordering are kept
+func (s *SopsManager) editManifest(edit func(*ManifestEditor) error) error {
+	editor, err := EditManifest(s.configPath)
+	if err != nil {
+		return err
 	}
-	manifest.Members = filtered
-	return nil
-}
-
-func (s *SopsManager) removeMemberFromAllScopes(manifest *Manifest, id string) {
-	for i := range manifest.Scopes {
-		s.removeMemberFromScope(&manifest.Scopes[i], id)
+	if err := edit(editor); err != nil {
+		return err
 	}
-}
-
-func (s *SopsManager) removeMemberFromScope(scope *Scope, id string) {
-	scope.Members = Filter(scope.Members, func(memberID string) bool { return memberID != id })
+	return editor.Save()
 }
 
 func (s *SopsManager) printRemovalSuccess(id string) {
-	_, _ = fmt.Fprintf(s.output, "Removed member %s from team\n", id)
-	_, _ = fmt.Fprintln(s.output, "Run 'sistry plan' to see changes, then 'sistry apply' to re-encrypt files")
+	s.reporter.Infof("Removed member %s from team", id)
+	s.reporter.Infof("Run 'sistry plan' to see changes, then 'sistry apply' to re-encrypt files")
 }
 
-// List displays current team configuration
-func (s *SopsManager) List(jsonOutput bool) error { //nolint:revive // jsonOutput is a legitimate CLI flag parameter
+// List displays current team configuration, optionally only the members
+// matching every label selector ("team=payments", or "team" for any value)
+func (s *SopsManager) List(jsonOutput bool, labels ...string) error { //nolint:revive // jsonOutput is a legitimate CLI flag parameter
 	manifest, err := LoadManifest(s.configPath)
 	if err != nil {
 		return fmt.Errorf(FailedToLoadManifestMsg, err)
 	}
 
+	// Only members with every given label are listed
+	for _, label := range labels {
+		selector, err := ParseLabelSelector(label)
+		if err != nil {
+			return fmt.Errorf("invalid --label %s: %w", label, err)
+		}
+		manifest = manifest.withMembers(selector.Matches)
+	}
+
 	if jsonOutput {
-		return manifest.DisplayJSON()
+		return WriteJSONResult(s.reporter.Out(), "list", manifest.ListResult())
+	}
+
+	manifest.Display(s.reporter.Out())
+	return nil
+}
+
+// Validate checks the manifest and reports every problem with its line and column
+func (s *SopsManager) Validate() error {
+	diagnostics, err := ValidateManifest(s.configPath)
+	if err != nil {
+		return err
 	}
 
-	manifest.Display()
+	var validationErr error
+	if len(diagnostics) > 0 {
+		validationErr = NewManifestError("validate", s.configPath, &ValidationError{Path: s.configPath, Diagnostics: diagnostics})
+	}
+
+	if s.jsonOutput {
+		result := ValidateResult{Valid: len(diagnostics) == 0, Problems: append([]Diagnostic{}, diagnostics...)}
+		if validationErr != nil {
+			return WriteJSONFailure(s.reporter.Out(), "validate", result, validationErr)
+		}
+		return WriteJSONResult(s.reporter.Out(), "validate", result)
+	}
+
+	if validationErr != nil {
+		return validationErr
+	}
+
+	s.reporter.Infof("✅ %s is valid", s.configPath)
 	return nil
 }
 
-// EncryptFile encrypts a file using the current team configuration
-func (s *SopsManager) EncryptFile(filePath string, inPlace bool, regex string) error {
+// EncryptFile encrypts a file for the members of the scope governing it.
+// The scope is resolved from scope patterns unless scopeName is given.
+func (s *SopsManager) EncryptFile(filePath, scopeName string, inPlace bool, regex string) error {
 	manifest, err := LoadManifest(s.configPath)
 	if err != nil {
 		return fmt.Errorf(FailedToLoadManifestMsg, err)
 	}
 
-	ageKeys := MapSlice(manifest.Members, func(m Member) string { return m.AgeKey })
+	planner := NewPlanner(s.sopsPath)
+	scope, err := planner.ResolveScope(manifest, filePath, scopeName)
+	if err != nil {
+		return err
+	}
 
-	if len(ageKeys) == 0 {
-		return fmt.Errorf("no team members found in configuration")
+	members, err := manifest.ScopeMembers(scope)
+	if err != nil {
+		return fmt.Errorf("failed to get members for scope %s: %w", scope.Name, err)
 	}
 
-	encryptor := NewEncryptor(s.sopsPath)
+	if len(members) == 0 {
+		return NewManifestError("validate", "scope "+scope.Name, fmt.Errorf("scope %s has no members", scope.Name))
+	}
+
+	s.printEncryptionScope(scope, members)
+
+	var ageKeys []string
+	for _, member := range members {
+		ageKeys = append(ageKeys, member.PublicKeys()...)
+	}
+	encryptor := NewEncryptor(s.sopsPath).WithReporter(s.reporter)
 	return encryptor.EncryptFile(filePath, ageKeys, inPlace, regex)
 }
 
+func (s *SopsManager) printEncryptionScope(scope *Scope, members []Member) {
+	memberIDs := MapSlice(members, func(m Member) string { return m.ID })
+	s.reporter.Infof("🎯 Scope: %s", scope.Name)
+	s.reporter.Infof("👥 Members: %s", strings.Join(memberIDs, ", "))
+}
+
 // DecryptFile decrypts a SOPS-encrypted file
 func (s *SopsManager) DecryptFile(filePath string, inPlace bool) error {
-	// Find current user's key
-	keyPath, _, err := s.findExistingKey()
+	metadata, err := ReadSOPSMetadata(filePath)
 	if err != nil {
-		return fmt.Errorf("failed to find decryption key: %w", err)
+		return NewCryptoError("decrypt", filePath, err)
 	}
-	if keyPath == "" {
-		return fmt.Errorf("no private key found in %s", s.secretsDir)
+	if !metadata.IsEncrypted() {
+		return NewCryptoError("decrypt", filePath, fmt.Errorf("%s is not encrypted with SOPS", filePath))
 	}
 
-	decryptor := NewDecryptor(s.sopsPath)
+	keyPath, err := s.findDecryptionKey(metadata)
+	if err != nil {
+		return fmt.Errorf("failed to find decryption key: %w", err)
+	}
+
+	decryptor := NewDecryptor(s.sopsPath).WithReporter(s.reporter)
 	return decryptor.DecryptFile(filePath, keyPath, inPlace)
 }
 
+// findDecryptionKey picks the local private key that is a recipient of the file,
+// falling back to any local key when the file has no age recipients
+func (s *SopsManager) findDecryptionKey(metadata *SOPSMetadata) (string, error) {
+	for _, recipient := range metadata.AgeRecipients {
+		if keyPath, err := s.findKeyForPublicKey(recipient); err == nil {
+			return keyPath, nil
+		}
+	}
+
+	if len(metadata.AgeRecipients) > 0 {
+		return "", NewKeyError("find", s.secretsDir, fmt.Errorf("none of the private keys in %s is a recipient of this file", s.secretsDir))
+	}
+
+	// Any local key will do: SOPS decrypts such files without an age key
+	keys, err := s.localKeys()
+	if err != nil {
+		return "", err
+	}
+	if len(keys) == 0 {
+		return "", NewKeyError("find", s.secretsDir, fmt.Errorf("no private key found in %s", s.secretsDir))
+	}
+	return keys[0].Path, nil
+}
+
+// CheckManagedFiles reports the SOPS encryption state of every file matched by a scope
+func (s *SopsManager) CheckManagedFiles() error {
+	statuses, err := s.managedFileStatuses()
+	if err != nil {
+		return err
+	}
+
+	for _, status := range statuses {
+		s.printFileState(status)
+	}
+
+	if len(statuses) == 0 {
+		s.reporter.Infof("  (no files match any scope)")
+	}
+	return nil
+}
+
+// CheckOrphanedFiles reports SOPS-encrypted files that no scope matches
+func (s *SopsManager) CheckOrphanedFiles() error {
+	orphans, err := s.orphanedFiles()
+	if err != nil {
+		return err
+	}
+
+	for _, orphan := range orphans {
+		s.reporter.Infof("📦 %s: %s", orphan.File, orphan.describeRecipients())
+	}
+
+	if len(orphans) == 0 {
+		s.reporter.Infof("  (none)")
+	} else {
+		s.reporter.Infof("  Bring them under a scope with 'sistry adopt --scope <scope> <file>'")
+	}
+	return nil
+}
+
+func (s *SopsManager) orphanedFiles() ([]OrphanedFile, error) {
+	manifest, err := LoadManifest(s.configPath)
+	if err != nil {
+		return nil, fmt.Errorf(FailedToLoadManifestMsg, err)
+	}
+	return NewPlanner(s.sopsPath).FindOrphans(manifest)
+}
+
+func (s *SopsManager) managedFileStatuses() ([]ManagedFileStatus, error) {
+	manifest, err := LoadManifest(s.configPath)
+	if err != nil {
+		return nil, fmt.Errorf(FailedToLoadManifestMsg, err)
+	}
+
+	planner := NewPlanner(s.sopsPath)
+	statuses := []ManagedFileStatus{}
+	for _, scope := range manifest.Scopes {
+		files, err := planner.findScopeFiles(manifest, scope)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find files for scope %s: %w", scope.Name, err)
+		}
+		for _, file := range files {
+			statuses = append(statuses, managedFileStatus(file, scope.Name))
+		}
+	}
+	return statuses, nil
+}
+
+func managedFileStatus(file, scopeName string) ManagedFileStatus {
+	status := ManagedFileStatus{File: file, Scope: scopeName}
+
+	metadata, err := ReadSOPSMetadata(file)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+
+	status.State = metadata.State
+	status.AgeRecipients = len(metadata.AgeRecipients)
+	return status
+}
+
+func (s *SopsManager) printFileState(status ManagedFileStatus) {
+	if status.Error != "" {
+		s.reporter.Infof("❌ %s (%s): %s", status.File, status.Scope, status.Error)
+		return
+	}
+
+	switch status.State {
+	case StateFullyEncrypted:
+		s.reporter.Infof("🔒 %s (%s): fully encrypted, %d age recipients",
+			status.File, status.Scope, status.AgeRecipients)
+	case StatePartiallyEncrypted:
+		s.reporter.Infof("🔐 %s (%s): partially encrypted, %d age recipients",
+			status.File, status.Scope, status.AgeRecipients)
+	case StatePlaintext:
+		s.reporter.Infof("⚠️  %s (%s): not encrypted", status.File, status.Scope)
+	}
+}
+
+// CheckReport gathers everything 'sistry check' reports and writes it as a JSON document.
+// Failures while gathering one section are recorded in the result rather than aborting,
+// but an expired key fails the command with a KeyError.
+func (s *SopsManager) CheckReport(sopsInfo *SOPSConfigInfo) error {
+	result := CheckResult{SOPSConfig: sopsInfo, Keys: []KeyStatus{}, Files: []ManagedFileStatus{}, Orphans: []OrphanedFile{}, Grants: []AccessGrant{}, Errors: []JSONError{}}
+
+	if keys, err := s.keyStatuses(true, time.Now()); err != nil {
+		result.Errors = append(result.Errors, *NewJSONError(err))
+	} else {
+		result.Keys = keys
+	}
+
+	if files, err := s.managedFileStatuses(); err != nil {
+		result.Errors = append(result.Errors, *NewJSONError(err))
+	} else {
+		result.Files = files
+	}
+
+	if orphans, err := s.orphanedFiles(); err != nil {
+		result.Errors = append(result.Errors, *NewJSONError(err))
+	} else {
+		result.Orphans = orphans
+	}
+
+	if manifest, err := LoadManifest(s.configPath); err != nil {
+		result.Errors = append(result.Errors, *NewJSONError(fmt.Errorf(FailedToLoadManifestMsg, err)))
+	} else {
+		result.Grants = manifest.AccessGrants(time.Now())
+	}
+
+	if err := expiredKeysError(result.Keys); err != nil {
+		return WriteJSONFailure(s.reporter.Out(), "check", result, err)
+	}
+	return WriteJSONResult(s.reporter.Out(), "check", result)
+}
+
 // ShowSOPSCommand displays the SOPS command with proper environment variables
 func (s *SopsManager) ShowSOPSCommand(args []string) error {
 	return s.handleSOPSCommand(args, false)
@@ -470,28 +844,31 @@ func (s *SopsManager) handleSOPSCommand(args []string, execute bool) error { //n
 		return fmt.Errorf(FailedToLoadManifestMsg, err)
 	}
 
-	ageKeys := MapSlice(manifest.Members, func(m Member) string { return m.AgeKey })
+	var ageKeys []string
+	for _, member := range Filter(manifest.Members, func(m Member) bool { return !m.IsSuspended() }) {
+		ageKeys = append(ageKeys, member.PublicKeys()...)
+	}
 
 	if len(ageKeys) == 0 {
-		return fmt.Errorf("no team members found in configuration")
+		return NewManifestError("validate", s.configPath, fmt.Errorf("no team members found in configuration"))
 	}
 
-	helper := NewSOPSHelper(s.sopsPath, s.secretsDir)
+	helper := NewSOPSHelper(s.sopsPath, s.secretsDir).WithReporter(s.reporter)
 	if execute {
 		return helper.ExecuteCommand(args, ageKeys)
 	}
 	return helper.ShowCommand(args, ageKeys)
 }
 
-// RotateKey rotates the current user's age key
+// RotateKey rotates the current user's age key on this device
 func (s *SopsManager) RotateKey(force bool) error {
-	manifest, currentMember, err := s.prepareKeyRotation(force)
+	manifest, currentMember, key, err := s.prepareKeyRotation(force)
 	if err != nil {
 		return err
 	}
 
-	// Find current user's key using their public key from manifest
-	keyPath, err := s.findKeyForPublicKey(currentMember.AgeKey)
+	// Find this device's private key using its public key from manifest
+	keyPath, err := s.findKeyForPublicKey(key.AgeKey)
 	if err != nil {
 		return fmt.Errorf("failed to find current user's private key: %w", err)
 	}
@@ -502,44 +879,54 @@ func (s *SopsManager) RotateKey(force bool) error {
 	}
 	defer func() { _ = os.Remove(backupPath) }() //nolint:errcheck // Cleanup backup file, error not critical
 
-	return s.executeKeyRotation(manifest, currentMember, keyPath, backupPath)
+	return s.executeKeyRotation(manifest, currentMember, key, keyPath, backupPath)
 }
 
-func (s *SopsManager) prepareKeyRotation(force bool) (*Manifest, *Member, error) { //nolint:revive // force is a legitimate CLI flag parameter
+func (s *SopsManager) prepareKeyRotation(force bool) (*Manifest, *Member, MemberKey, error) { //nolint:revive // force is a legitimate CLI flag parameter
 	manifest, err := LoadManifest(s.configPath)
 	if err != nil {
-		return nil, nil, fmt.Errorf(FailedToLoadManifestMsg, err)
+		return nil, nil, MemberKey{}, fmt.Errorf(FailedToLoadManifestMsg, err)
 	}
 
 	currentUser, err := s.getCurrentMemberID()
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, MemberKey{}, err
 	}
 
-	currentMember := s.findMemberByID(manifest, currentUser)
+	currentMember := manifest.FindMember(currentUser)
 	if currentMember == nil {
-		return nil, nil, fmt.Errorf("current user %s not found in team", currentUser)
+		return nil, nil, MemberKey{}, NewKeyError("rotate", currentUser, fmt.Errorf("current user %s not found in team", currentUser))
+	}
+
+	key, err := s.localMemberKey(currentMember)
+	if err != nil {
+		return nil, nil, MemberKey{}, err
 	}
 
 	if !force {
-		if err := s.checkKeyExpiry(currentMember, manifest.Settings.MaxKeyAgeDays); err != nil {
-			return nil, nil, err
+		if err := s.checkKeyExpiry(currentMember.ID, key, manifest.Settings.maxKeyAgeDays(*currentMember)); err != nil {
+			return nil, nil, MemberKey{}, err
 		}
 	}
 
-	return manifest, currentMember, nil
+	return manifest, currentMember, key, nil
 }
 
-func (s *SopsManager) findMemberByID(manifest *Manifest, userID string) *Member {
-	for i := range manifest.Members {
-		if manifest.Members[i].ID == userID {
-			return &manifest.Members[i]
+// localMemberKey returns the member's key whose private key is on this device
+func (s *SopsManager) localMemberKey(member *Member) (MemberKey, error) {
+	keys := member.AgeKeys()
+	for _, key := range keys {
+		if _, err := s.findKeyForPublicKey(key.AgeKey); err == nil {
+			return key, nil
 		}
 	}
-	return nil
+	if len(keys) == 1 {
+		return keys[0], nil // Let the caller report the missing private key
+	}
+	return MemberKey{}, NewKeyError("rotate", member.ID, fmt.Errorf("no private key in %s matches any of %s's keys", s.secretsDir, member.ID))
 }
 
-func (s *SopsManager) executeKeyRotation(manifest *Manifest, currentMember *Member, keyPath, backupPath string) error {
+func (s *SopsManager) executeKeyRotation(manifest *Manifest, currentMember *Member, key MemberKey, keyPath, backupPath string) error {
 	// Generate new key with hash-based naming
 	newPublicKey, err := s.generateNewAgeKey()
 	if err != nil {
@@ -549,13 +936,24 @@ func (s *SopsManager) executeKeyRotation(manifest *Manifest, currentMember *Memb
 	// Remove old key file (backup was already made)
 	if err := os.Remove(keyPath); err != nil && !os.IsNotExist(err) {
 		// Non-critical - log but continue
-		_, _ = fmt.Fprintf(s.output, "Warning: failed to remove old key file %s: %v\n", keyPath, err)
+		s.reporter.Warnf("Warning: failed to remove old key file %s: %v", keyPath, err)
 	}
 
-	currentMember.AgeKey = newPublicKey
-	currentMember.Created = time.Now().UTC()
+	key.AgeKey = newPublicKey
+	key.Created = time.Now().UTC()
+	if i := slices.IndexFunc(currentMember.Keys, func(k MemberKey) bool { return k.Label == key.Label }); key.Label != "" && i >= 0 {
+		currentMember.Keys[i] = key
+	} else {
+		currentMember.AgeKey, currentMember.Created = key.AgeKey, key.Created
+	}
 
-	if err := manifest.Save(s.configPath); err != nil {
+	err = s.editManifest(func(editor *ManifestEditor) error {
+		if key.Label != "" {
+			return editor.SetMemberDeviceKey(currentMember.ID, key.Label, key.AgeKey, key.Created)
+		}
+		return editor.SetMemberKey(currentMember.ID, key.AgeKey, key.Created)
+	})
+	if err != nil {
 		return s.handleRotationError("failed to save manifest", err, keyPath, backupPath)
 	}
 
@@ -563,7 +961,7 @@ func (s *SopsManager) executeKeyRotation(manifest *Manifest, currentMember *Memb
 		return err
 	}
 
-	s.printRotationSuccess(currentMember)
+	s.printRotationSuccess(keyName(currentMember.ID, key.Label), key.Created)
 	return nil
 }
 
@@ -574,17 +972,17 @@ func (s *SopsManager) reencryptAllFiles(manifest *Manifest, keyPath, backupPath
 		return s.handleRotationError("failed to compute plan", err, keyPath, backupPath)
 	}
 
-	executor := NewExecutor(s.sopsPath)
-	if err := executor.Execute(plan); err != nil {
+	executor := NewExecutor(s.sopsPath).WithReporter(s.reporter)
+	if _, err := executor.Execute(plan); err != nil {
 		return s.handleRotationError("failed to re-encrypt files", err, keyPath, backupPath)
 	}
 
 	return nil
 }
 
-func (s *SopsManager) printRotationSuccess(member *Member) {
-	_, _ = fmt.Fprintf(s.output, "🔄 Successfully rotated key for %s\n", member.ID)
-	_, _ = fmt.Fprintf(s.output, "📅 New key created: %s\n", member.Created.Format("2006-01-02T15:04:05Z"))
+func (s *SopsManager) printRotationSuccess(name string, created time.Time) {
+	s.reporter.Infof("🔄 Successfully rotated key for %s", name)
+	s.reporter.Infof("📅 New key created: %s", created.Format("2006-01-02T15:04:05Z"))
 }
 
 func (s *SopsManager) backupCurrentKey(keyPath, backupPath string) error {
@@ -617,80 +1015,154 @@ func (s *SopsManager) handleRotationError(msg string, err error, keyPath, backup
 	return fmt.Errorf("%s: %w", msg, err)
 }
 
-func (s *SopsManager) checkKeyExpiry(member *Member, maxAgeDays int) error {
-	maxAgeDays = max(maxAgeDays, DefaultMaxKeyAgeDays) // ensure minimum of 180 days (6 months)
-
-	age := time.Since(member.Created)
+func (s *SopsManager) checkKeyExpiry(memberID string, key MemberKey, maxAgeDays int) error {
+	age := time.Since(key.Created)
 	maxAge := time.Duration(maxAgeDays) * HoursPerDay * time.Hour
 
 	if age > maxAge {
-		return fmt.Errorf("key has expired (age: %d days, max: %d days). Use --force to rotate anyway",
-			int(age.Hours()/24), maxAgeDays)
+		return NewKeyError("rotate", keyName(memberID, key.Label), fmt.Errorf("key has expired (age: %d days, max: %d days). Use --force to rotate anyway",
+			int(age.Hours()/24), maxAgeDays))
 	}
 
 	return nil
 }
 
-// CheckKeyExpiry checks if any keys are expired or expiring soon
-func (s *SopsManager) CheckKeyExpiry(verbose bool) error {
-	manifest, err := LoadManifest(s.configPath)
+// CheckKeyExpiry checks if any keys are expired or expiring soon, returning a
+// KeyError once every key is reported if any has expired.
+// Local private key files are only looked up and shown in verbose mode.
+func (s *SopsManager) CheckKeyExpiry() error {
+	verbose := s.reporter.Level() >= LevelVerbose
+	statuses, err := s.keyStatuses(verbose, time.Now())
 	if err != nil {
-		return fmt.Errorf(FailedToLoadManifestMsg, err)
+		return err
 	}
 
-	maxAgeDays := max(manifest.Settings.MaxKeyAgeDays, DefaultMaxKeyAgeDays) // ensure minimum of 180 days (6 months)
-
 	warnings := 0
 	errors := 0
-	now := time.Now()
-
-	for _, member := range manifest.Members {
-		memberWarnings, memberErrors := s.checkMemberKeyStatus(member, maxAgeDays, now, verbose)
-		warnings += memberWarnings
-		errors += memberErrors
+	machineErrors := 0
+	for i, status := range statuses {
+		if status.Machine && (i == 0 || !statuses[i-1].Machine) {
+			s.reporter.Infof("Machine members:")
+		}
+		s.printKeyStatus(status, verbose)
+		switch {
+		case status.State == KeyExpired && status.Machine:
+			machineErrors++
+		case status.State == KeyExpired:
+			errors++
+		case status.State == KeyExpiring:
+			warnings++
+		}
 	}
 
 	if errors > 0 {
-		_, _ = fmt.Fprintf(s.output, "\n%d expired keys found. Run 'sistry rotate-key' to rotate.\n", errors)
+		s.reporter.Infof("\n%d expired keys found. Run 'sistry rotate-key' to rotate.", errors)
+	}
+	if machineErrors > 0 {
+		s.reporter.Infof("\n%d expired machine keys found. Replace them with 'sistry remove-member' and 'sistry member add-machine'.", machineErrors)
 	}
 	if warnings > 0 {
-		_, _ = fmt.Fprintf(s.output, "\n%d keys expiring soon. Consider running 'sistry rotate-key'.\n", warnings)
+		s.reporter.Infof("\n%d keys expiring soon. Consider running 'sistry rotate-key'.", warnings)
 	}
 
-	return nil
+	return expiredKeysError(statuses)
+}
+
+// expiredKeysError returns a KeyError naming the members with an expired key,
+// or nil when no key has expired
+func expiredKeysError(statuses []KeyStatus) error {
+	var ids []string
+	for _, status := range statuses {
+		if status.State == KeyExpired && !slices.Contains(ids, status.Member) {
+			ids = append(ids, status.Member)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return NewKeyError("expiry", strings.Join(ids, ", "), fmt.Errorf("age key expired"))
+}
+
+// keyStatuses classifies every key of every member by its age at now
+func (s *SopsManager) keyStatuses(findPrivateKeys bool, now time.Time) ([]KeyStatus, error) { //nolint:revive // findPrivateKeys selects an optional, slower lookup
+	manifest, err := LoadManifest(s.configPath)
+	if err != nil {
+		return nil, fmt.Errorf(FailedToLoadManifestMsg, err)
+	}
+
+	// People first, then machine members, which check lists separately
+	members := append(Filter(manifest.Members, func(m Member) bool { return !m.IsMachine() }),
+		Filter(manifest.Members, Member.IsMachine)...)
+
+	statuses := []KeyStatus{}
+	for _, member := range members {
+		maxAgeDays := manifest.Settings.maxKeyAgeDays(member)
+		for _, key := range member.AgeKeys() {
+			status := s.memberKeyStatus(member.ID, key, maxAgeDays, now, findPrivateKeys)
+			status.Machine = member.IsMachine()
+			status.Suspended = member.IsSuspended()
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses, nil
 }
 
-// checkMemberKeyStatus checks a single member's key status and returns warnings/errors count
-func (s *SopsManager) checkMemberKeyStatus(member Member, maxAgeDays int, now time.Time, verbose bool) (warnings, errors int) { //nolint:revive // verbose is a legitimate CLI flag parameter
-	age := now.Sub(member.Created)
+// memberKeyStatus classifies a single key of a member
+func (s *SopsManager) memberKeyStatus(memberID string, key MemberKey, maxAgeDays int, now time.Time, findPrivateKey bool) KeyStatus { //nolint:revive // findPrivateKey selects an optional, slower lookup
+	age := now.Sub(key.Created)
 	maxAge := time.Duration(maxAgeDays) * HoursPerDay * time.Hour
 	warningThreshold := maxAge - warningThresholdHours
 
-	// Find matching private key file for verbose output
-	var keyInfo string
-	if verbose {
-		keyPath, err := s.findKeyForPublicKey(member.AgeKey)
-		if err != nil {
-			keyInfo = " [private key: NOT FOUND]"
-		} else {
-			keyInfo = fmt.Sprintf(" [private key: %s]", filepath.Base(keyPath))
+	status := KeyStatus{
+		Member:        memberID,
+		Label:         key.Label,
+		Created:       key.Created,
+		AgeDays:       int(age.Hours() / 24),
+		DaysRemaining: int((maxAge - age).Hours() / 24),
+	}
+
+	// Find matching private key file, which runs age-keygen for every local key
+	if findPrivateKey {
+		if keyPath, err := s.findKeyForPublicKey(key.AgeKey); err == nil {
+			status.PrivateKey = filepath.Base(keyPath)
 		}
 	}
 
 	switch {
 	case age > maxAge:
-		_, _ = fmt.Fprintf(s.output, "❌ %s: key expired %d days ago (created: %s)%s\n",
-			member.ID, int((age-maxAge).Hours()/24), member.Created.Format(DateFormat), keyInfo)
-		return 0, 1 // 0 warnings, 1 error
+		status.State = KeyExpired
 	case age > warningThreshold:
-		daysUntilExpiry := int((maxAge - age).Hours() / 24)
-		_, _ = fmt.Fprintf(s.output, "⚠️  %s: key expires in %d days (created: %s)%s\n",
-			member.ID, daysUntilExpiry, member.Created.Format(DateFormat), keyInfo)
-		return 1, 0 // 1 warning, 0 errors
+		status.State = KeyExpiring
 	default:
-		daysAge := int(age.Hours() / 24)
-		_, _ = fmt.Fprintf(s.output, "✅ %s: key is %d days old (created: %s)%s\n",
-			member.ID, daysAge, member.Created.Format(DateFormat), keyInfo)
-		return 0, 0 // 0 warnings, 0 errors
+		status.State = KeyOK
+	}
+	return status
+}
+
+func (s *SopsManager) printKeyStatus(status KeyStatus, verbose bool) { //nolint:revive // verbose is a legitimate CLI flag parameter
+	var keyInfo string
+	if verbose {
+		if status.PrivateKey == "" {
+			keyInfo = " [private key: NOT FOUND]"
+		} else {
+			keyInfo = fmt.Sprintf(" [private key: %s]", status.PrivateKey)
+		}
+	}
+
+	name := keyName(status.Member, status.Label)
+	if status.Suspended {
+		name += " (suspended)"
+	}
+	created := status.Created.Format(DateFormat)
+	switch status.State {
+	case KeyExpired:
+		s.reporter.Infof("❌ %s: key expired %d days ago (created: %s)%s",
+			name, -status.DaysRemaining, created, keyInfo)
+	case KeyExpiring:
+		s.reporter.Infof("⚠️  %s: key expires in %d days (created: %s)%s",
+			name, status.DaysRemaining, created, keyInfo)
+	case KeyOK:
+		s.reporter.Infof("✅ %s: key is %d days old (created: %s)%s",
+			name, status.AgeDays, created, keyInfo)
 	}
 }
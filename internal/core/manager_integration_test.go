@@ -89,7 +89,7 @@ func setupIntegrationTestEnvironment(t *testing.T) *SopsManager {
 		sopsPath:   "sops",
 		configPath: filepath.Join(tempDir, "sopsistry.yaml"),
 		secretsDir: filepath.Join(tempDir, ".secrets"),
-		output:     io.Discard, // Silent output for tests
+		reporter:   NewWriterReporter(io.Discard), // Silent output for tests
 	}
 }
 
@@ -124,7 +124,7 @@ func TestSopsManager_Init_ForceWithoutActiveKeys(t *testing.T) {
 	service := setupTestEnvironment(t)
 
 	// Create manifest file but no key file
-	manifest := service.createInitialManifest("testuser", "age1testkey123", time.Now().UTC())
+	manifest := service.createInitialManifest("testuser", testAgeKeyValue, time.Now().UTC())
 	err := manifest.Save(service.configPath)
 	requireNoError(t, err, "should create initial manifest")
 
@@ -318,7 +318,7 @@ func createSopsManagerInDir(workDir string) *SopsManager {
 		sopsPath:   "sops",
 		configPath: filepath.Join(workDir, "sopsistry.yaml"),
 		secretsDir: filepath.Join(workDir, ".secrets"),
-		output:     io.Discard, // Silent output for tests
+		reporter:   NewWriterReporter(io.Discard), // Silent output for tests
 	}
 }
 
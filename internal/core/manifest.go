@@ -1,9 +1,12 @@
 package core
 
 import (
-	"encoding/json"
 	"fmt"
+	"io"
+	"maps"
 	"os"
+	"slices"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -11,89 +14,169 @@ import (
 
 // Member represents a team member with their age key
 type Member struct {
-	Created time.Time `yaml:"created" json:"created"`
-	ID      string    `yaml:"id" json:"id"`
-	AgeKey  string    `yaml:"age_key" json:"age_key"`
+	Created time.Time  `yaml:"created" json:"created"`
+	ID      string     `yaml:"id" json:"id"`
+	Type    MemberType `yaml:"type,omitempty" json:"type,omitempty"` // human (default) or machine; see machines.go
+	AgeKey  string     `yaml:"age_key" json:"age_key"`
+	// Keys of the member's other devices; see member_keys.go
+	Keys []MemberKey `yaml:"keys,omitempty" json:"keys,omitempty"`
+	// When access was suspended; zero while active. See suspend.go
+	Suspended time.Time `yaml:"suspended,omitempty" json:"suspended,omitzero"`
+	// Who approved the member's join request; see join.go
+	Approved *Approval `yaml:"approved,omitempty" json:"approved,omitempty"`
+
+	// Optional details shown by 'sistry list'; see member_metadata.go
+	Name      string            `yaml:"name,omitempty" json:"name,omitempty"`
+	Email     string            `yaml:"email,omitempty" json:"email,omitempty"`
+	GitEmails []string          `yaml:"git_emails,omitempty" json:"git_emails,omitempty"` // Commit author emails, for matching commits to members
+	Notes     string            `yaml:"notes,omitempty" json:"notes,omitempty"`
+	Labels    map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"` // Free-form key/value pairs, e.g. team: payments
 }
 
 // Scope defines which files are encrypted for which members
 type Scope struct {
 	Name     string   `yaml:"name" json:"name"`
-	Patterns []string `yaml:"patterns" json:"patterns"`
+	Patterns []string `yaml:"patterns" json:"patterns"` // gitignore-style; see pattern.go
+	Exclude  []string `yaml:"exclude,omitempty" json:"exclude,omitempty"`
 	Members  []string `yaml:"members" json:"members"`
+	// Last day of access of time-limited member entries, written as
+	// {member: bob, until: 2026-11-01} among members; see grants.go
+	Until map[string]time.Time `yaml:"-" json:"until,omitempty"`
+	// Scopes whose members, including inherited ones, can also read this scope
+	InheritsMembersFrom []string `yaml:"inherits_members_from,omitempty" json:"inherits_members_from,omitempty"`
 }
 
 // Settings contains global configuration
 type Settings struct {
 	SopsVersion   string `yaml:"sops_version" json:"sops_version"`
 	MaxKeyAgeDays int    `yaml:"max_key_age_days,omitempty" json:"max_key_age_days,omitempty"`
+	// Key lifetime of machine members, which is not raised to the 180 day minimum of human keys
+	MaxMachineKeyAgeDays int    `yaml:"max_machine_key_age_days,omitempty" json:"max_machine_key_age_days,omitempty"`
+	FileDiscovery        string `yaml:"file_discovery,omitempty" json:"file_discovery,omitempty"` // auto (default), git or filesystem
+	ScopeOverlap         string `yaml:"scope_overlap,omitempty" json:"scope_overlap,omitempty"`   // error (default), union, first or most-specific
 }
 
 // Manifest represents the sopsistry.yaml configuration
 type Manifest struct {
-	Members  []Member `yaml:"members" json:"members"`
-	Scopes   []Scope  `yaml:"scopes" json:"scopes"`
-	Settings Settings `yaml:"settings" json:"settings"`
+	Members       []Member            `yaml:"members" json:"members"`
+	Groups        map[string][]string `yaml:"groups,omitempty" json:"groups,omitempty"` // Member IDs and nested @groups, referenced from scopes as @name
+	Scopes        []Scope             `yaml:"scopes" json:"scopes"`
+	DefaultScopes []string            `yaml:"default_scopes,omitempty" json:"default_scopes,omitempty"` // Scopes new members join unless told otherwise
+	Settings      Settings            `yaml:"settings" json:"settings"`
 }
 
-// LoadManifest loads the team manifest from file
+// LoadManifest loads the team manifest from file and validates it.
+// All problems are reported at once in a ValidationError.
 func LoadManifest(path string) (*Manifest, error) {
-	data, err := os.ReadFile(path) //nolint:gosec // Reading user-provided config file is expected
+	manifest, diagnostics, err := parseManifest(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read manifest: %w", err)
+		return nil, err
 	}
-
-	var manifest Manifest
-	if err := yaml.Unmarshal(data, &manifest); err != nil {
-		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	if len(diagnostics) > 0 {
+		return nil, NewManifestError("validate", path, &ValidationError{Path: path, Diagnostics: diagnostics})
 	}
 
-	return &manifest, nil
+	return manifest, nil
 }
 
 // Save writes the manifest to file
 func (m *Manifest) Save(path string) error {
 	data, err := yaml.Marshal(m)
 	if err != nil {
-		return fmt.Errorf("failed to marshal manifest: %w", err)
+		return NewManifestError("marshal", path, err)
 	}
 
 	if err := os.WriteFile(path, data, GitignoreFileMode); err != nil {
-		return fmt.Errorf("failed to write manifest: %w", err)
+		return NewManifestError("save", path, err)
 	}
 
 	return nil
 }
 
-// Display shows the manifest in human-readable format
-func (m *Manifest) Display() {
-	fmt.Println("Team Members:")
-	if len(m.Members) == 0 {
-		fmt.Println("  (none)")
-	} else {
-		for _, member := range m.Members {
-			fmt.Printf("  %s: %s\n", member.ID, member.AgeKey[:16]+"...")
+// Display shows the manifest in human-readable format.
+// It is the primary output of 'sistry list', so it is shown even in quiet mode.
+func (m *Manifest) Display(w io.Writer) {
+	humans := Filter(m.Members, func(member Member) bool { return !member.IsMachine() })
+	machines := Filter(m.Members, Member.IsMachine)
+
+	_, _ = fmt.Fprintln(w, "Team Members:")
+	if len(humans) == 0 {
+		_, _ = fmt.Fprintln(w, "  (none)")
+	}
+	m.displayMembers(w, humans)
+
+	if len(machines) > 0 {
+		_, _ = fmt.Fprintln(w, "\nMachine Members:")
+		m.displayMembers(w, machines)
+	}
+
+	if len(m.Groups) > 0 {
+		_, _ = fmt.Fprintln(w, "\nGroups:")
+		for _, name := range slices.Sorted(maps.Keys(m.Groups)) {
+			_, _ = fmt.Fprintf(w, "  %s: %v\n", groupPrefix+name, m.Groups[name])
 		}
 	}
 
-	fmt.Println("\nScopes:")
+	_, _ = fmt.Fprintln(w, "\nScopes:")
 	for _, scope := range m.Scopes {
-		fmt.Printf("  %s:\n", scope.Name)
-		fmt.Printf("    Patterns: %v\n", scope.Patterns)
-		fmt.Printf("    Members: %v\n", scope.Members)
+		_, _ = fmt.Fprintf(w, "  %s:\n", scope.Name)
+		_, _ = fmt.Fprintf(w, "    Patterns: %v\n", scope.Patterns)
+		if len(scope.Exclude) > 0 {
+			_, _ = fmt.Fprintf(w, "    Exclude: %v\n", scope.Exclude)
+		}
+		_, _ = fmt.Fprintf(w, "    Members: %v\n", MapSlice(scope.Members, func(entry string) string {
+			if until, ok := scope.Until[entry]; ok {
+				return entry + " (until " + until.Format(DateFormat) + ")"
+			}
+			return entry
+		}))
+		if len(scope.InheritsMembersFrom) > 0 {
+			_, _ = fmt.Fprintf(w, "    Inherits members from: %v\n", scope.InheritsMembersFrom)
+		}
+	}
+
+	if len(m.DefaultScopes) > 0 {
+		_, _ = fmt.Fprintf(w, "\nDefault scopes: %v\n", m.DefaultScopes)
 	}
 
-	fmt.Printf("\nSettings:\n")
-	fmt.Printf("  SOPS Version: %s\n", m.Settings.SopsVersion)
+	_, _ = fmt.Fprintf(w, "\nSettings:\n")
+	_, _ = fmt.Fprintf(w, "  SOPS Version: %s\n", m.Settings.SopsVersion)
 }
 
-// DisplayJSON outputs the manifest as JSON
-func (m *Manifest) DisplayJSON() error {
-	data, err := json.MarshalIndent(m, "", "  ")
-	if err != nil {
-		return err
+func (m *Manifest) displayMembers(w io.Writer, members []Member) {
+	for _, member := range members {
+		suspended := ""
+		if member.IsSuspended() {
+			suspended = " (suspended since " + member.Suspended.Format(DateFormat) + ")"
+		}
+		_, _ = fmt.Fprintf(w, "  %s: %s%s\n", member.ID, shortenKey(member.AgeKey), suspended)
+		for _, key := range member.Keys {
+			_, _ = fmt.Fprintf(w, "    Key %s: %s\n", key.Label, shortenKey(key.AgeKey))
+		}
+		displayMemberMetadata(w, member)
+		if access := m.MemberScopes(member.ID, time.Now()); len(access) > 0 {
+			_, _ = fmt.Fprintf(w, "    Scopes: %s\n", strings.Join(MapSlice(access, ScopeAccess.String), ", "))
+		}
+	}
+}
+
+// ListResult returns the manifest for 'sistry list --json', along with each member's effective scopes
+func (m *Manifest) ListResult() ListResult {
+	now := time.Now()
+	memberScopes := make(map[string][]ScopeAccess, len(m.Members))
+	for _, member := range m.Members {
+		memberScopes[member.ID] = append([]ScopeAccess{}, m.MemberScopes(member.ID, now)...)
+	}
+	return ListResult{Manifest: m, MemberScopes: memberScopes}
+}
+
+// FindMember returns the member with the given ID, or nil if there is none
+func (m *Manifest) FindMember(id string) *Member {
+	for i := range m.Members {
+		if m.Members[i].ID == id {
+			return &m.Members[i]
+		}
 	}
-	fmt.Println(string(data))
 	return nil
 }
 
@@ -107,28 +190,52 @@ func (m *Manifest) GetMemberAgeKey(id string) (string, bool) {
 	return "", false
 }
 
-// GetScopeMembers returns all members for a given scope
-func (m *Manifest) GetScopeMembers(scopeName string) ([]Member, error) {
-	var scope *Scope
+// MemberIDsForKey returns the IDs of all members using the given age key on any device
+func (m *Manifest) MemberIDsForKey(key string) []string {
+	var ids []string
+	for _, member := range m.Members {
+		if slices.Contains(member.PublicKeys(), key) {
+			ids = append(ids, member.ID)
+		}
+	}
+	return ids
+}
+
+// FindScope returns the scope with the given name, or nil if there is none
+func (m *Manifest) FindScope(name string) *Scope {
 	for i := range m.Scopes {
-		if m.Scopes[i].Name == scopeName {
-			scope = &m.Scopes[i]
-			break
+		if m.Scopes[i].Name == name {
+			return &m.Scopes[i]
 		}
 	}
+	return nil
+}
 
-	if scope == nil {
-		return nil, fmt.Errorf("scope %s not found", scopeName)
+// MemberDefaultScopes returns the scopes a new member joins when none are given
+func (m *Manifest) MemberDefaultScopes() []string {
+	if len(m.DefaultScopes) > 0 {
+		return m.DefaultScopes
+	}
+	// Manifests created before default_scopes existed rely on a scope named "default"
+	if m.FindScope("default") != nil {
+		return []string{"default"}
 	}
+	return nil
+}
 
-	var members []Member //nolint:prealloc // Small team sizes, optimization not worth it
-	for _, memberID := range scope.Members {
-		ageKey, found := m.GetMemberAgeKey(memberID)
-		if !found {
-			return nil, fmt.Errorf("member %s not found", memberID)
-		}
-		members = append(members, Member{ID: memberID, AgeKey: ageKey})
+// GetScopeMembers returns all members for a given scope
+func (m *Manifest) GetScopeMembers(scopeName string) ([]Member, error) {
+	scope := m.FindScope(scopeName)
+	if scope == nil {
+		return nil, NewManifestError("validate", "scope "+scopeName, fmt.Errorf("scope %s not found", scopeName))
 	}
+	return m.ScopeMembers(scope)
+}
 
-	return members, nil
+// ScopeMembers returns the current members of scope, with groups expanded and
+// members inherited from other scopes included. The scope need not be one of
+// the manifest's own scopes (a union of overlapping scopes, for instance).
+func (m *Manifest) ScopeMembers(scope *Scope) ([]Member, error) {
+	members, _, err := m.ScopeMembership(scope, time.Now())
+	return members, err
 }
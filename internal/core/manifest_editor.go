@@ -0,0 +1,559 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const defaultManifestIndent = 2
+
+// ManifestEditor changes a manifest file in place by editing only the affected
+// YAML nodes, so comments, anchors and key order of a hand-curated
+// sopsistry.yaml survive 'add-member', 'remove-member' and 'rotate-key'. Save
+// splices the changed lines into the original bytes, keeping the blank lines
+// and spacing yaml.v3 itself would drop.
+type ManifestEditor struct {
+	root     *yaml.Node // Top-level mapping of the document
+	doc      *yaml.Node
+	path     string
+	original []byte
+	indent   int
+}
+
+// EditManifest opens the manifest at path for editing
+func EditManifest(path string) (*ManifestEditor, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // Reading user-provided config file is expected
+	if err != nil {
+		return nil, NewManifestError("load", path, err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, NewManifestError("parse", path, err)
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, NewManifestError("parse", path, fmt.Errorf("top level must be a mapping"))
+	}
+
+	return &ManifestEditor{root: doc.Content[0], doc: &doc, path: path, original: data, indent: detectIndent(data)}, nil
+}
+
+// AddMember appends member to the members list
+func (e *ManifestEditor) AddMember(member Member) error {
+	members, err := e.sequence("members")
+	if err != nil {
+		return err
+	}
+	fields := map[string]any{
+		"id":      member.ID,
+		"age_key": member.AgeKey,
+		"created": member.Created,
+	}
+	if member.Type != "" {
+		fields["type"] = member.Type
+	}
+	if member.Approved != nil {
+		fields["approved"] = member.Approved
+	}
+	item, err := newItemLike(members, fields, []string{"id", "type", "age_key", "created", "approved"})
+	if err != nil {
+		return err
+	}
+
+	members.Content = append(members.Content, item)
+	return nil
+}
+
+// RemoveMember removes the member with the given ID from the members list
+// and from every scope and group. A scope or group listing the member through
+// an alias is refused, as for additions.
+func (e *ManifestEditor) RemoveMember(id string) error {
+	members, err := e.sequence("members")
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(members.Content, func(n *yaml.Node) bool { return scalarValue(mappingValue(n, "id")) == id })
+	if i < 0 {
+		return fmt.Errorf("member %s not found", id)
+	}
+
+	// Check every list before editing any, since removing id from an anchored
+	// list hides it from the aliases of that list
+	lists := e.memberLists()
+	for _, list := range lists {
+		if err := list.refuseAlias(id); err != nil {
+			return err
+		}
+	}
+
+	members.Content = slices.Delete(members.Content, i, i+1)
+	for _, list := range lists {
+		if list.node.Kind == yaml.SequenceNode {
+			list.node.Content = slices.DeleteFunc(list.node.Content, func(n *yaml.Node) bool { return list.entry(n) == id })
+		}
+	}
+	return nil
+}
+
+// memberList is a list naming members: the members of a scope, or a group
+type memberList struct {
+	node  *yaml.Node
+	entry func(*yaml.Node) string // Member ID or @group an item names
+	owner string                  // "scope production" or "group backend"
+}
+
+// memberLists returns the member list of every scope and group, as written
+func (e *ManifestEditor) memberLists() []memberList {
+	var lists []memberList
+	for _, scope := range e.items("scopes") {
+		if i := mappingKeyIndex(scope, "members"); i >= 0 {
+			lists = append(lists, memberList{node: scope.Content[i+1], entry: scopeEntry, owner: "scope " + scalarValue(mappingValue(scope, "name"))})
+		}
+	}
+	if groups := mappingValue(e.root, "groups"); groups != nil && groups.Kind == yaml.MappingNode {
+		for i := 1; i < len(groups.Content); i += 2 {
+			lists = append(lists, memberList{node: groups.Content[i], entry: scalarValue, owner: "group " + groups.Content[i-1].Value})
+		}
+	}
+	return lists
+}
+
+// refuseAlias returns an error when the list is an alias naming id: editing
+// it would change every place the anchored list is used
+func (l memberList) refuseAlias(id string) error {
+	if l.node.Kind != yaml.AliasNode {
+		return nil
+	}
+	target := resolveAlias(l.node)
+	if target.Kind == yaml.SequenceNode && slices.ContainsFunc(target.Content, func(n *yaml.Node) bool { return l.entry(n) == id }) {
+		return fmt.Errorf("%s lists %s through the alias *%s; remove %s from the anchored list, or replace the alias with a list of its own", l.owner, id, l.node.Value, id)
+	}
+	return nil
+}
+
+// AddScope appends a new scope
+func (e *ManifestEditor) AddScope(scope Scope) error {
+	if e.scope(scope.Name) != nil {
+		return fmt.Errorf("scope %s already exists", scope.Name)
+	}
+
+	scopes, err := e.sequence("scopes")
+	if err != nil {
+		return err
+	}
+	item, err := newItemLike(scopes, map[string]any{
+		"name":     scope.Name,
+		"patterns": nonNil(scope.Patterns),
+		"members":  nonNil(scope.Members),
+	}, []string{"name", "patterns", "members"})
+	if err != nil {
+		return err
+	}
+
+	scopes.Content = append(scopes.Content, item)
+	return nil
+}
+
+// RemoveScope removes the named scope and drops it from default_scopes. A
+// scope other scopes inherit members from is refused, since removing it would
+// silently revoke access through them.
+func (e *ManifestEditor) RemoveScope(name string) error {
+	scopes, err := e.sequence("scopes")
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(scopes.Content, func(n *yaml.Node) bool { return scalarValue(mappingValue(n, "name")) == name })
+	if i < 0 {
+		return fmt.Errorf("scope %s not found", name)
+	}
+
+	var inheritors []string
+	for _, scope := range scopes.Content {
+		if inherits := mappingValue(scope, "inherits_members_from"); inherits != nil && slices.ContainsFunc(inherits.Content, func(n *yaml.Node) bool { return n.Value == name }) {
+			inheritors = append(inheritors, scalarValue(mappingValue(scope, "name")))
+		}
+	}
+	if len(inheritors) > 0 {
+		return fmt.Errorf("scope %s is inherited by: %s; remove it from their inherits_members_from first", name, strings.Join(inheritors, ", "))
+	}
+	scopes.Content = slices.Delete(scopes.Content, i, i+1)
+
+	if defaults := mappingValue(e.root, "default_scopes"); defaults != nil && defaults.Kind == yaml.SequenceNode {
+		defaults.Content = slices.DeleteFunc(defaults.Content, func(n *yaml.Node) bool { return n.Value == name })
+	}
+	return nil
+}
+
+// RenameScope renames a scope and every reference to it in default_scopes and
+// inherits_members_from; its members and patterns are kept as they are
+func (e *ManifestEditor) RenameScope(oldName, newName string) error {
+	scope := e.scope(oldName)
+	if scope == nil {
+		return fmt.Errorf("scope %s not found", oldName)
+	}
+	if e.scope(newName) != nil {
+		return fmt.Errorf("scope %s already exists", newName)
+	}
+
+	lists := []*yaml.Node{mappingValue(e.root, "default_scopes")}
+	for _, item := range e.items("scopes") {
+		lists = append(lists, mappingValue(item, "inherits_members_from"))
+	}
+	for _, list := range lists {
+		if list == nil || list.Kind != yaml.SequenceNode {
+			continue
+		}
+		for _, item := range list.Content {
+			if item.Value == oldName {
+				item.Value = newName
+			}
+		}
+	}
+	return setMappingValue(scope, "name", newName)
+}
+
+// AddScopeMember grants member id, or every member of an @group, access to the named scope
+func (e *ManifestEditor) AddScopeMember(scopeName, id string) error {
+	if err := e.checkMemberRef(id); err != nil {
+		return err
+	}
+	return e.addToScopeList(scopeName, "members", id)
+}
+
+// checkMemberRef checks that id names an existing member or @group
+func (e *ManifestEditor) checkMemberRef(id string) error {
+	if group, isGroup := groupRef(id); isGroup {
+		if mappingValue(mappingValue(e.root, "groups"), group) == nil {
+			return fmt.Errorf("group %s not found", group)
+		}
+	} else if e.member(id) == nil {
+		return fmt.Errorf("member %s not found", id)
+	}
+	return nil
+}
+
+// AddScopeGrant grants member id, or a @group, access to the named scope through until
+func (e *ManifestEditor) AddScopeGrant(scopeName, id string, until time.Time) error {
+	if err := e.checkMemberRef(id); err != nil {
+		return err
+	}
+	scope := e.scope(scopeName)
+	if scope == nil {
+		return fmt.Errorf("scope %s not found", scopeName)
+	}
+	return e.appendToScopeList(scope, scopeName, "members", id, newGrantNode(id, until))
+}
+
+// RemoveScopeMember revokes member id's access to the named scope
+func (e *ManifestEditor) RemoveScopeMember(scopeName, id string) error {
+	return e.removeFromScopeList(scopeName, "members", id)
+}
+
+// AddScopePattern adds a file pattern to the named scope
+func (e *ManifestEditor) AddScopePattern(scopeName, pattern string) error {
+	return e.addToScopeList(scopeName, "patterns", pattern)
+}
+
+// RemoveScopePattern removes a file pattern from the named scope
+func (e *ManifestEditor) RemoveScopePattern(scopeName, pattern string) error {
+	return e.removeFromScopeList(scopeName, "patterns", pattern)
+}
+
+func (e *ManifestEditor) addToScopeList(scopeName, key, value string) error {
+	scope := e.scope(scopeName)
+	if scope == nil {
+		return fmt.Errorf("scope %s not found", scopeName)
+	}
+
+	return e.appendToScopeList(scope, scopeName, key, value, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value})
+}
+
+func (e *ManifestEditor) appendToScopeList(scope *yaml.Node, scopeName, key, value string, item *yaml.Node) error {
+	list, err := ensureSequence(scope, key)
+	if err != nil {
+		return fmt.Errorf("scope %s: %w", scopeName, err)
+	}
+	if slices.ContainsFunc(list.Content, func(n *yaml.Node) bool { return scopeEntry(n) == value }) {
+		return fmt.Errorf("scope %s already has %s %s", scopeName, strings.TrimSuffix(key, "s"), value)
+	}
+	list.Content = append(list.Content, item)
+	return nil
+}
+
+func (e *ManifestEditor) removeFromScopeList(scopeName, key, value string) error {
+	scope := e.scope(scopeName)
+	if scope == nil {
+		return fmt.Errorf("scope %s not found", scopeName)
+	}
+
+	list, err := ensureSequence(scope, key)
+	if err != nil {
+		return fmt.Errorf("scope %s: %w", scopeName, err)
+	}
+	i := slices.IndexFunc(list.Content, func(n *yaml.Node) bool { return scopeEntry(n) == value })
+	if i < 0 {
+		return fmt.Errorf("scope %s has no %s %s", scopeName, strings.TrimSuffix(key, "s"), value)
+	}
+	list.Content = slices.Delete(list.Content, i, i+1)
+	return nil
+}
+
+// SetMemberKey replaces the age key and creation time of member id
+func (e *ManifestEditor) SetMemberKey(id, ageKey string, created time.Time) error {
+	member := e.member(id)
+	if member == nil {
+		return fmt.Errorf("member %s not found", id)
+	}
+	if err := setMappingValue(member, "age_key", ageKey); err != nil {
+		return err
+	}
+	return setMappingValue(member, "created", created)
+}
+
+// AddMemberKey adds a labeled device key to member id
+func (e *ManifestEditor) AddMemberKey(id string, key MemberKey) error {
+	member := e.member(id)
+	if member == nil {
+		return fmt.Errorf("member %s not found", id)
+	}
+	if e.memberKey(member, key.Label) != nil {
+		return fmt.Errorf("member %s already has a key labeled %s", id, key.Label)
+	}
+
+	keys, err := ensureSequence(member, "keys")
+	if err != nil {
+		return fmt.Errorf("member %s: %w", id, err)
+	}
+	item, err := newItemLike(keys, map[string]any{
+		"label":   key.Label,
+		"age_key": key.AgeKey,
+		"created": key.Created,
+	}, []string{"label", "age_key", "created"})
+	if err != nil {
+		return err
+	}
+
+	keys.Content = append(keys.Content, item)
+	return nil
+}
+
+// SetMemberDeviceKey replaces the age key and creation time of member id's
+// key with the given label
+func (e *ManifestEditor) SetMemberDeviceKey(id, label, ageKey string, created time.Time) error {
+	member := e.member(id)
+	if member == nil {
+		return fmt.Errorf("member %s not found", id)
+	}
+	key := e.memberKey(member, label)
+	if key == nil {
+		return fmt.Errorf("member %s has no key labeled %s", id, label)
+	}
+	if err := setMappingValue(key, "age_key", ageKey); err != nil {
+		return err
+	}
+	return setMappingValue(key, "created", created)
+}
+
+// SetMemberSuspended suspends member id since the given time, or resumes
+// them when it is zero
+func (e *ManifestEditor) SetMemberSuspended(id string, since time.Time) error {
+	member := e.member(id)
+	if member == nil {
+		return fmt.Errorf("member %s not found", id)
+	}
+	if since.IsZero() {
+		if i := mappingKeyIndex(member, "suspended"); i >= 0 {
+			member.Content = slices.Delete(member.Content, i, i+2)
+		}
+		return nil
+	}
+	return setMappingValue(member, "suspended", since)
+}
+
+// Save validates the edited document and writes it back to the manifest file.
+// An edit that leaves the manifest invalid is refused and the file is left as it was.
+func (e *ManifestEditor) Save() error {
+	data, err := encodeNode(e.doc, e.indent)
+	if err != nil {
+		return NewManifestError("marshal", e.path, err)
+	}
+	if before, err := encodeYAML(e.original, e.indent); err == nil {
+		if spliced, ok := spliceEdits(e.original, before, data, e.indent); ok {
+			data = spliced
+		}
+	}
+
+	_, diagnostics, err := parseManifestData(e.path, data)
+	if err != nil {
+		return err
+	}
+	if len(diagnostics) > 0 {
+		return NewManifestError("validate", e.path, &ValidationError{Path: e.path, Diagnostics: diagnostics})
+	}
+
+	if err := os.WriteFile(e.path, data, GitignoreFileMode); err != nil {
+		return NewManifestError("save", e.path, err)
+	}
+	return nil
+}
+
+// member returns the mapping node of member id, or nil
+func (e *ManifestEditor) member(id string) *yaml.Node {
+	for _, member := range e.items("members") {
+		if scalarValue(mappingValue(member, "id")) == id {
+			return member
+		}
+	}
+	return nil
+}
+
+// memberKey returns the mapping node of the member's key with the given label, or nil
+func (e *ManifestEditor) memberKey(member *yaml.Node, label string) *yaml.Node {
+	keys := mappingValue(member, "keys")
+	if keys == nil || keys.Kind != yaml.SequenceNode {
+		return nil
+	}
+	for _, key := range keys.Content {
+		if scalarValue(mappingValue(key, "label")) == label {
+			return key
+		}
+	}
+	return nil
+}
+
+// scopeEntry returns the value of a scope list item: the member of a
+// time-limited grant, and the scalar itself otherwise
+func scopeEntry(item *yaml.Node) string {
+	if item.Kind == yaml.MappingNode {
+		return scalarValue(mappingValue(item, "member"))
+	}
+	return item.Value
+}
+
+// scope returns the mapping node of the named scope, or nil
+func (e *ManifestEditor) scope(name string) *yaml.Node {
+	for _, scope := range e.items("scopes") {
+		if scalarValue(mappingValue(scope, "name")) == name {
+			return scope
+		}
+	}
+	return nil
+}
+
+// sequence returns the top-level sequence for key to edit, creating it if missing
+func (e *ManifestEditor) sequence(key string) (*yaml.Node, error) {
+	return ensureSequence(e.root, key)
+}
+
+// items returns the items of the top-level sequence for key, following an alias
+func (e *ManifestEditor) items(key string) []*yaml.Node {
+	if list := mappingValue(e.root, key); list != nil && list.Kind == yaml.SequenceNode {
+		return list.Content
+	}
+	return nil
+}
+
+// newItemLike builds a mapping node from fields, ordering keys like the
+// first existing item in sequence so new entries match hand-written ones
+func newItemLike(sequence *yaml.Node, fields map[string]any, defaultOrder []string) (*yaml.Node, error) {
+	order := defaultOrder
+	if len(sequence.Content) > 0 && sequence.Content[0].Kind == yaml.MappingNode {
+		order = mappingKeys(sequence.Content[0])
+		for _, key := range defaultOrder {
+			if !slices.Contains(order, key) {
+				order = append(order, key)
+			}
+		}
+	}
+
+	item := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, key := range order {
+		value, ok := fields[key]
+		if !ok {
+			continue
+		}
+		if err := setMappingValue(item, key, value); err != nil {
+			return nil, err
+		}
+	}
+	return item, nil
+}
+
+// setMappingValue sets key in a mapping node to value, keeping the position
+// and comments of an existing key
+func setMappingValue(node *yaml.Node, key string, value any) error {
+	var encoded yaml.Node
+	if err := encoded.Encode(value); err != nil {
+		return NewManifestError("marshal", key, err)
+	}
+
+	if i := mappingKeyIndex(node, key); i >= 0 {
+		existing := node.Content[i+1] // Replacing an alias leaves its anchor untouched
+		existing.Kind, existing.Tag, existing.Value, existing.Content, existing.Alias = encoded.Kind, encoded.Tag, encoded.Value, encoded.Content, nil
+		return nil
+	}
+
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, &encoded)
+	return nil
+}
+
+// ensureSequence returns the sequence value for key, replacing a missing or
+// null value with an empty sequence. A list written as an alias is refused:
+// editing it would change every place the anchored list is used.
+func ensureSequence(node *yaml.Node, key string) (*yaml.Node, error) {
+	var value *yaml.Node
+	if i := mappingKeyIndex(node, key); i >= 0 {
+		value = node.Content[i+1]
+	}
+	switch {
+	case value != nil && value.Kind == yaml.AliasNode:
+		return nil, fmt.Errorf("%s is an alias of &%s; edit the anchored list, or replace the alias with a list of its own", key, value.Value)
+	case value != nil && value.Kind == yaml.SequenceNode:
+		return value, nil
+	case value == nil:
+		value = &yaml.Node{}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+	}
+	value.Kind, value.Tag, value.Value, value.Style = yaml.SequenceNode, "!!seq", "", 0
+	return value, nil
+}
+
+// nonNil returns items, or an empty slice so that it is written as [] rather than null
+func nonNil(items []string) []string {
+	if items == nil {
+		return []string{}
+	}
+	return items
+}
+
+func mappingKeys(node *yaml.Node) []string {
+	var keys []string
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keys = append(keys, node.Content[i].Value)
+	}
+	return keys
+}
+
+func scalarValue(node *yaml.Node) string {
+	if node == nil || node.Kind != yaml.ScalarNode {
+		return ""
+	}
+	return node.Value
+}
+
+// detectIndent returns the indentation width of the first indented line in data
+func detectIndent(data []byte) int {
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" || trimmed == line || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		return len(line) - len(trimmed)
+	}
+	return defaultManifestIndent
+}
@@ -0,0 +1,205 @@
+package core
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const curatedManifest = `# Team secrets, reviewed quarterly
+members:
+  - id: alice # team lead
+    age_key: ` + testRecipientA + `
+  - id: bob
+    age_key: ` + testRecipientB + `
+scopes:
+  - name: default
+    patterns: ["*.env"]
+    members: [alice, bob]
+  - name: production
+    patterns:
+      - prod/*
+    members:
+      - alice
+      # contractor with prod access until the migration is done
+      - bob
+settings:
+  sops_version: 3.8.0
+`
+
+func TestSopsManager_MemberEditsPreserveComments(t *testing.T) {
+	t.Parallel()
+
+	// Given: a hand-curated manifest with comments
+	tempDir := t.TempDir()
+	configPath := writeTestFile(t, tempDir, "sopsistry.yaml", curatedManifest)
+	var output bytes.Buffer
+	service := &SopsManager{sopsPath: "sops", configPath: configPath, secretsDir: ".secrets", reporter: NewWriterReporter(&output)}
+
+	// When: adding carol and removing bob
+	requireNoError(t, service.AddMember("carol", testRecipientC), "adding member should succeed")
+	requireNoError(t, service.RemoveMember("bob"), "removing member should succeed")
+
+	// Then: only the affected nodes change; comments and layout stay
+	want := `# Team secrets, reviewed quarterly
+members:
+  - id: alice # team lead
+    age_key: ` + testRecipientA + `
+  - id: carol
+    age_key: ` + testRecipientC + `
+    created: CREATED
+scopes:
+  - name: default
+    patterns: ["*.env"]
+    members: [alice, carol]
+  - name: production
+    patterns:
+      - prod/*
+    members:
+      - alice
+settings:
+  sops_version: 3.8.0
+`
+	manifest := loadManifestOrFail(t, configPath)
+	created := manifest.Members[1].Created.Format("2006-01-02T15:04:05.999999999Z07:00")
+	data, err := os.ReadFile(configPath)
+	requireNoError(t, err, "reading manifest should succeed")
+	if got, want := string(data), strings.ReplaceAll(want, "CREATED", created); got != want {
+		t.Errorf("unexpected manifest:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestManifestEditor_SetMemberKeyKeepsIndentAndOrder(t *testing.T) {
+	t.Parallel()
+
+	// Given: a manifest written with four-space indentation and created first
+	tempDir := t.TempDir()
+	original := createSampleManifest()
+	path := filepath.Join(tempDir, "sopsistry.yaml")
+	requireNoError(t, original.Save(path), "saving manifest should succeed")
+	before, err := os.ReadFile(path)
+	requireNoError(t, err, "reading manifest should succeed")
+
+	// When: changing bob's key to a new value and back
+	editor, err := EditManifest(path)
+	requireNoError(t, err, "opening manifest should succeed")
+	requireNoError(t, editor.SetMemberKey("bob", testRecipientD, original.Members[1].Created), "setting key should succeed")
+	requireNoError(t, editor.SetMemberKey("bob", original.Members[1].AgeKey, original.Members[1].Created), "setting key should succeed")
+	requireNoError(t, editor.Save(), "saving manifest should succeed")
+
+	// Then: the file is byte-for-byte unchanged
+	after, err := os.ReadFile(path)
+	requireNoError(t, err, "reading manifest should succeed")
+	if !bytes.Equal(before, after) {
+		t.Errorf("expected no changes, got:\n%s\nwant:\n%s", after, before)
+	}
+
+	// When: a member that does not exist is edited
+	err = editor.SetMemberKey("mallory", testRecipientD, original.Members[1].Created)
+
+	// Then: it is refused
+	requireError(t, err, "unknown member should be refused")
+}
+
+func TestSopsManager_EditsKeepAnchoredMemberLists(t *testing.T) {
+	t.Parallel()
+
+	// Given: production shares development's member list through an alias
+	tempDir := t.TempDir()
+	configPath := writeTestFile(t, tempDir, "sopsistry.yaml", `members:
+  - id: alice
+    age_key: `+testRecipientA+`
+  - id: bob
+    age_key: `+testRecipientB+`
+  - id: carol
+    age_key: `+testRecipientC+`
+scopes:
+  - name: development
+    patterns: ["dev/*"]
+    members: &devs [alice, bob]
+  - name: production
+    patterns: ["prod/*"]
+    members: *devs
+`)
+	var output bytes.Buffer
+	service := &SopsManager{sopsPath: "sops", configPath: configPath, secretsDir: ".secrets", reporter: NewWriterReporter(&output)}
+
+	// When: adding carol and removing bob through the alias, then through the anchored list
+	errAdd := service.AddScopeMember("production", "carol")
+	errRemove := service.RemoveMember("bob")
+	requireNoError(t, service.AddScopeMember("development", "carol"), "adding to the anchored list should succeed")
+	requireNoError(t, service.RemoveScopeMember("development", "bob"), "removing from the anchored list should succeed")
+	requireNoError(t, service.RemoveMember("bob"), "removing a member no alias lists should succeed")
+
+	// Then: editing through the alias is refused, and the other edits keep the anchor and alias
+	if errAdd == nil || !containsString(errAdd.Error(), "members is an alias of &devs") {
+		t.Errorf("expected adding through the alias to be refused, got %v", errAdd)
+	}
+	if errRemove == nil || !containsString(errRemove.Error(), "scope production lists bob through the alias *devs") {
+		t.Errorf("expected removing through the alias to be refused, got %v", errRemove)
+	}
+	data, err := os.ReadFile(configPath) //nolint:gosec // Test file in temp dir
+	requireNoError(t, err, "reading manifest should succeed")
+	if !containsString(string(data), "members: &devs [alice, carol]") || !containsString(string(data), "members: *devs\n") {
+		t.Errorf("expected anchor and alias to be kept, got:\n%s", data)
+	}
+	if members := loadManifestOrFail(t, configPath).FindScope("production").Members; strings.Join(members, ",") != "alice,carol" {
+		t.Errorf("expected production to read the anchored list, got %v", members)
+	}
+}
+
+func TestSopsManager_EditsKeepBlankLinesAndSpacing(t *testing.T) {
+	t.Parallel()
+
+	// Given: a hand-written manifest with blank lines and aligned comments
+	tempDir := t.TempDir()
+	configPath := writeTestFile(t, tempDir, "sopsistry.yaml", `members:
+  - id: alice    # team lead
+    age_key: `+testRecipientA+`
+
+  - id: bob      # backend
+    age_key: `+testRecipientB+`
+
+scopes:
+
+  - name: development
+    patterns: ["dev/*"]
+    members: [alice, bob]
+
+  - name: production
+    patterns: ["prod/*"]
+    members:
+      - alice    # on call
+`)
+	var output bytes.Buffer
+	service := &SopsManager{sopsPath: "sops", configPath: configPath, secretsDir: ".secrets", reporter: NewWriterReporter(&output)}
+
+	// When: adding bob to production, renaming development and removing alice
+	requireNoError(t, service.AddScopeMember("production", "bob"), "adding member should succeed")
+	requireNoError(t, service.RenameScope("development", "dev"), "renaming should succeed")
+	requireNoError(t, service.RemoveMember("alice"), "removing member should succeed")
+
+	// Then: only the edited lines change
+	want := `members:
+  - id: bob      # backend
+    age_key: ` + testRecipientB + `
+
+scopes:
+
+  - name: dev
+    patterns: ["dev/*"]
+    members: [bob]
+
+  - name: production
+    patterns: ["prod/*"]
+    members:
+      - bob
+`
+	data, err := os.ReadFile(configPath) //nolint:gosec // Test file in temp dir
+	requireNoError(t, err, "reading manifest should succeed")
+	if string(data) != want {
+		t.Errorf("unexpected manifest:\n%s\nwant:\n%s", data, want)
+	}
+}
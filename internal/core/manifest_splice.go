@@ -0,0 +1,160 @@
+package core
+
+import (
+	"bytes"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// spliceEdits carries an edit over to the original manifest bytes, so that
+// blank lines and spacing yaml.v3 does not keep survive outside the edited
+// lines. before and after are the encodings of the document before and after
+// the edit; the lines that differ between them replace the matching lines of
+// original. It reports false when original cannot be lined up with before,
+// or the result would not decode like after.
+func spliceEdits(original, before, after []byte, indent int) ([]byte, bool) {
+	originalLines, beforeLines, afterLines := splitLines(original), splitLines(before), splitLines(after)
+
+	// Line up the original with its re-encoding: only blank lines may be left over
+	mapped := make([]int, len(beforeLines))
+	for i := range mapped {
+		mapped[i] = -1
+	}
+	for _, pair := range commonLines(originalLines, beforeLines, func(o, b string) bool { return o != "" && normalizeLine(o) == normalizeLine(b) }) {
+		mapped[pair[1]] = pair[0]
+	}
+	if !lineUpComplete(originalLines, mapped) {
+		return nil, false
+	}
+
+	var out []string
+	var pendingGap []string // Blank lines before lines edited away
+	pendingIndent := -1     // Indentation of the first line edited away, or -1
+	next := 0               // First original line not emitted or dropped yet
+	gapBefore := func(i int) []string {
+		gap := originalLines[next:mapped[i]]
+		next = mapped[i] + 1
+		return gap
+	}
+
+	b, a := 0, 0
+	emitUntil := func(toBefore, toAfter int) {
+		for ; b < toBefore; b++ { // Edited away: drop the line, keep its gap for what follows
+			gap := gapBefore(b)
+			if pendingIndent < 0 {
+				pendingGap, pendingIndent = gap, lineIndent(originalLines[mapped[b]])
+			}
+		}
+		for ; a < toAfter; a++ {
+			out = append(out, pendingGap...)
+			pendingGap, pendingIndent = nil, -1
+			out = append(out, afterLines[a])
+		}
+	}
+	for _, pair := range commonLines(beforeLines, afterLines, func(x, y string) bool { return x == y }) {
+		emitUntil(pair[0], pair[1])
+		// A line taking the place of removed siblings takes their gap, while
+		// an outer line keeps its own, such as the blank line before scopes:
+		gap, line := gapBefore(b), originalLines[mapped[b]]
+		if pendingIndent >= 0 && lineIndent(line) >= pendingIndent {
+			gap = pendingGap
+		}
+		out = append(out, gap...)
+		out = append(out, line)
+		pendingGap, pendingIndent = nil, -1
+		b, a = b+1, a+1
+	}
+	emitUntil(len(beforeLines), len(afterLines))
+	out = append(out, originalLines[next:]...)
+
+	spliced := []byte(strings.Join(out, "\n") + "\n")
+	if reencoded, err := encodeYAML(spliced, indent); err != nil || !bytes.Equal(reencoded, after) {
+		return nil, false
+	}
+	return spliced, true
+}
+
+// lineUpComplete reports whether every re-encoded line has its original line
+// and every original line left over is blank
+func lineUpComplete(originalLines []string, mapped []int) bool {
+	used := make([]bool, len(originalLines))
+	for _, o := range mapped {
+		if o < 0 {
+			return false
+		}
+		used[o] = true
+	}
+	for o, line := range originalLines {
+		if !used[o] && strings.TrimSpace(line) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// commonLines returns the index pairs of a longest common subsequence of a and b
+func commonLines(a, b []string, equal func(x, y string) bool) [][2]int {
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if equal(a[i], b[j]) {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+
+	var pairs [][2]int
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case equal(a[i], b[j]) && lengths[i][j] == lengths[i+1][j+1]+1:
+			pairs = append(pairs, [2]int{i, j})
+			i, j = i+1, j+1
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return pairs
+}
+
+func lineIndent(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// normalizeLine collapses runs of whitespace, which yaml.v3 does not keep
+func normalizeLine(line string) string {
+	return strings.Join(strings.Fields(line), " ")
+}
+
+func splitLines(data []byte) []string {
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+// encodeYAML decodes data and encodes it again as yaml.v3 writes it
+func encodeYAML(data []byte, indent int) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return encodeNode(&doc, indent)
+}
+
+func encodeNode(doc *yaml.Node, indent int) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(indent)
+	if err := encoder.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
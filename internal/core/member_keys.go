@@ -0,0 +1,130 @@
+package core
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// MemberKey is one of a member's age keys, typically one per device. A
+// member's age_key is their unlabeled first key; further devices are listed
+// under keys with a label.
+type MemberKey struct {
+	Created time.Time `yaml:"created" json:"created"`
+	Label   string    `yaml:"label" json:"label"`
+	AgeKey  string    `yaml:"age_key" json:"age_key"`
+}
+
+// keyName identifies a member's key in output, as "alice" or "alice/desktop"
+func keyName(memberID, label string) string {
+	if label == "" {
+		return memberID
+	}
+	return memberID + "/" + label
+}
+
+// AgeKeys returns all of the member's keys, age_key first
+func (m Member) AgeKeys() []MemberKey {
+	keys := make([]MemberKey, 0, len(m.Keys)+1)
+	if m.AgeKey != "" {
+		keys = append(keys, MemberKey{AgeKey: m.AgeKey, Created: m.Created})
+	}
+	return append(keys, m.Keys...)
+}
+
+// PublicKeys returns the age public keys of all of the member's devices
+func (m Member) PublicKeys() []string {
+	return MapSlice(m.AgeKeys(), func(k MemberKey) string { return k.AgeKey })
+}
+
+// AddMemberKey registers an additional device key for a member. Without
+// ageKey, the local key in the secrets directory is used if it is not
+// registered yet, and a new one is generated otherwise.
+func (s *SopsManager) AddMemberKey(memberID, label, ageKey string) error {
+	manifest, err := LoadManifest(s.configPath)
+	if err != nil {
+		return fmt.Errorf(FailedToLoadManifestMsg, err)
+	}
+
+	if memberID == "" {
+		if memberID, err = s.getCurrentMemberID(); err != nil {
+			return err
+		}
+	}
+	member := manifest.FindMember(memberID)
+	if member == nil {
+		return NewManifestError("validate", "member "+memberID, fmt.Errorf("member %s not found", memberID))
+	}
+	if err := validateKeyLabel(member, label); err != nil {
+		return NewManifestError("validate", "member "+memberID, err)
+	}
+
+	if ageKey == "" {
+		if ageKey, err = s.localKeyForNewDevice(manifest, memberID); err != nil {
+			return err
+		}
+	} else if _, err := NewAgePublicKey(ageKey); err != nil {
+		return NewManifestError("validate", "member "+memberID, err)
+	}
+	if owners := manifest.MemberIDsForKey(ageKey); len(owners) > 0 {
+		return NewManifestError("validate", "member "+memberID, fmt.Errorf("key is already registered for %s", strings.Join(owners, ", ")))
+	}
+
+	key := MemberKey{Label: label, AgeKey: ageKey, Created: time.Now().UTC()}
+	if err := s.editManifest(func(editor *ManifestEditor) error { return editor.AddMemberKey(memberID, key) }); err != nil {
+		return fmt.Errorf("failed to save manifest: %w", err)
+	}
+
+	if s.jsonOutput {
+		return WriteJSONResult(s.reporter.Out(), "key add", KeyResult{Member: memberID, Label: label, AgeKey: ageKey, Change: "added"})
+	}
+
+	s.reporter.Infof("Added key %s for %s: %s", label, memberID, ageKey)
+	s.reporter.Infof("Run 'sistry plan' to see changes, then 'sistry apply' to re-encrypt files for the new key")
+	return nil
+}
+
+// validateKeyLabel checks that label is usable for a new key of member
+func validateKeyLabel(member *Member, label string) error {
+	if _, err := NewKeyLabel(label); err != nil {
+		return err
+	}
+	if slices.ContainsFunc(member.Keys, func(k MemberKey) bool { return k.Label == label }) {
+		return fmt.Errorf("member %s already has a key labeled %s", member.ID, label)
+	}
+	return nil
+}
+
+// localKeyForNewDevice returns the public key of this machine's private key
+// that is not registered for anyone yet, generating one when every local key
+// is registered. Several unregistered keys are an error rather than a guess.
+func (s *SopsManager) localKeyForNewDevice(manifest *Manifest, memberID string) (string, error) {
+	keys, err := s.localKeys()
+	if err != nil {
+		return "", err
+	}
+
+	unregistered := Filter(keys, func(k localKey) bool { return len(manifest.MemberIDsForKey(k.PublicKey)) == 0 })
+	switch {
+	case len(unregistered) == 1:
+		s.reporter.Infof("Using existing age key at %s", unregistered[0].Path)
+		return unregistered[0].PublicKey, nil
+	case len(unregistered) > 1:
+		return "", s.ambiguousKeysError(memberID, unregistered)
+	case len(keys) > 0:
+		var owners []string
+		for _, key := range keys {
+			owners = append(owners, manifest.MemberIDsForKey(key.PublicKey)...)
+		}
+		owners = slices.Compact(slices.Sorted(slices.Values(owners)))
+		return "", NewKeyError("add", strings.Join(owners, ", "), fmt.Errorf(
+			"the private keys in %s are already registered for %s; run this on the new device, or pass --key with its public key",
+			s.secretsDir, strings.Join(owners, ", ")))
+	}
+
+	if _, err := s.setupEnvironment(); err != nil {
+		return "", err
+	}
+	return s.generateNewAgeKey()
+}
@@ -0,0 +1,183 @@
+package core
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPlanner_EncryptsForEveryMemberKey(t *testing.T) {
+	t.Parallel()
+
+	// Given: alice has a desktop key besides her laptop's age_key, and prod.env
+	// is only encrypted for the laptop
+	tempDir := t.TempDir()
+	prodFile := writeTestFile(t, tempDir, "prod.env", sopsDotenvContent(testRecipientA))
+	manifest := createScopedManifest(tempDir)
+	manifest.Members[0].Keys = []MemberKey{{Label: "desktop", AgeKey: testRecipientD}}
+
+	// When: planning
+	plan, err := NewPlanner("sops").ComputePlan(manifest)
+
+	// Then: the file is re-encrypted for both keys, and the new device is shown
+	requireNoError(t, err, "computing plan should succeed")
+	action := findActionForFile(t, plan, prodFile)
+	if action.Type != ActionReencrypt {
+		t.Errorf("expected re-encryption, got %s", action.Type)
+	}
+	if !slices.Equal(action.Recipients, []string{testRecipientA, testRecipientD}) {
+		t.Errorf("expected both of alice's keys as recipients, got %v", action.Recipients)
+	}
+	if len(action.AddedRecipients) != 0 || !slices.Equal(action.AddedKeys, []string{"alice/desktop"}) {
+		t.Errorf("expected only alice/desktop to be added, got %v and %v", action.AddedRecipients, action.AddedKeys)
+	}
+
+	var output bytes.Buffer
+	plan.Display(NewWriterReporter(&output))
+	if !containsString(output.String(), "Access: +alice/desktop") {
+		t.Errorf("expected the new key in plan output, got: %s", output.String())
+	}
+}
+
+func TestSopsManager_KeyStatusesPerDevice(t *testing.T) {
+	t.Parallel()
+
+	// Given: alice's laptop key is old and her desktop key is new
+	service, _, _ := setupScopeTest(t)
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	requireNoError(t, service.editManifest(func(editor *ManifestEditor) error {
+		if err := editor.SetMemberKey("alice", testRecipientA, now.AddDate(0, 0, -400)); err != nil {
+			return err
+		}
+		return editor.AddMemberKey("alice", MemberKey{Label: "desktop", AgeKey: testRecipientD, Created: now.AddDate(0, 0, -10)})
+	}), "editing manifest should succeed")
+
+	// When: classifying keys
+	statuses, err := service.keyStatuses(false, now)
+
+	// Then: each key has its own state
+	requireNoError(t, err, "key statuses should succeed")
+	states := MapSlice(statuses, func(s KeyStatus) string { return keyName(s.Member, s.Label) + "=" + string(s.State) })
+	if !slices.Contains(states, "alice=expired") || !slices.Contains(states, "alice/desktop=ok") {
+		t.Errorf("expected separate states for alice's keys, got %v", states)
+	}
+}
+
+func TestSopsManager_AddMemberKey(t *testing.T) {
+	t.Parallel()
+
+	// Given: a team where carol uses testRecipientC
+	service, output, _ := setupScopeTest(t)
+
+	// When: registering a desktop key for alice
+	requireNoError(t, service.AddMemberKey("alice", "desktop", testRecipientD), "adding key should succeed")
+
+	// Then: the key is saved, and labels and keys cannot be reused
+	manifest := loadManifestOrFail(t, service.configPath)
+	alice := manifest.FindMember("alice")
+	if len(alice.Keys) != 1 || alice.Keys[0].Label != "desktop" || alice.Keys[0].AgeKey != testRecipientD || alice.Keys[0].Created.IsZero() {
+		t.Errorf("expected alice's desktop key, got %+v", alice.Keys)
+	}
+	if !containsString(output.String(), "Added key desktop for alice") {
+		t.Errorf("expected confirmation, got: %s", output.String())
+	}
+	requireError(t, service.AddMemberKey("alice", "desktop", testRecipientB), "duplicate labels should be refused")
+	requireError(t, service.AddMemberKey("alice", "ci", testRecipientC), "keys of other members should be refused")
+	requireError(t, service.AddMemberKey("mallory", "ci", testRecipientB), "unknown members should be refused")
+}
+
+func TestLoadManifest_ValidatesMemberKeys(t *testing.T) {
+	t.Parallel()
+
+	// Given: a member with a duplicate label and an invalid device key
+	path := writeTestFile(t, t.TempDir(), "sopsistry.yaml", `members:
+  - id: alice
+    age_key: `+testRecipientA+`
+    keys:
+      - label: desktop
+        age_key: `+testRecipientD+`
+      - label: desktop
+        age_key: not-a-key
+scopes:
+  - name: default
+    patterns: ["*.env"]
+    members: [alice]
+`)
+
+	// When: loading it
+	_, err := LoadManifest(path)
+
+	// Then: both problems are reported
+	requireError(t, err, "invalid keys should be refused")
+	for _, want := range []string{
+		`7:16: member alice has more than one key labeled "desktop"`,
+		"8:18: member alice key desktop: invalid age public key format",
+	} {
+		if !containsString(err.Error(), want) {
+			t.Errorf("expected %q in error, got: %v", want, err)
+		}
+	}
+}
+
+// useFakeAgeKeygen puts an age-keygen on PATH whose -y reads the public key
+// from the "# public key:" line of the key file, as written by writeLocalKey
+func useFakeAgeKeygen(t *testing.T) {
+	t.Helper()
+
+	binDir := t.TempDir()
+	script := "#!/bin/sh\n[ \"$1\" = -y ] || exit 1\nsed -n 's/^# public key: //p' \"$2\"\n"
+	requireNoError(t, os.WriteFile(filepath.Join(binDir, AgeKeygenBinary), []byte(script), 0o700), "writing fake age-keygen should succeed") //nolint:gosec // Test helper script must be executable
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+// writeLocalKey stores a private key file for publicKey in secretsDir
+func writeLocalKey(t *testing.T, secretsDir, publicKey string) string {
+	t.Helper()
+
+	content := "# public key: " + publicKey + "\nAGE-SECRET-KEY-1" + strings.ToUpper(publicKey[4:]) + "\n"
+	requireNoError(t, os.MkdirAll(secretsDir, BackupDirMode), "creating secrets directory should succeed")
+	path := filepath.Join(secretsDir, "key-"+keyHashFromPrivateKey(content)+".txt")
+	requireNoError(t, os.WriteFile(path, []byte(content), PrivateKeyFileMode), "writing key should succeed")
+	return path
+}
+
+func TestSopsManager_LocalKeysAreMatchedAgainstTheManifest(t *testing.T) {
+	// Not parallel: the fake age-keygen is put on PATH
+
+	// Given: this machine holds alice's registered key and an unregistered one
+	useFakeAgeKeygen(t)
+	service, _, tempDir := setupScopeTest(t)
+	service.secretsDir = filepath.Join(tempDir, ".secrets")
+	writeLocalKey(t, service.secretsDir, testRecipientA)
+	newKey := writeLocalKey(t, service.secretsDir, testRecipientD)
+	manifest := loadManifestOrFail(t, service.configPath)
+
+	// When: looking up alice's key, and the key for her new device
+	keyPath, publicKey, err := service.findExistingKey(manifest, "alice")
+	requireNoError(t, err, "alice's key should be found")
+	requireNoError(t, service.AddMemberKey("alice", "desktop", ""), "adding the local key should succeed")
+
+	// Then: each picks the key matching the manifest, whatever the file order
+	if publicKey != testRecipientA || filepath.Base(keyPath) == filepath.Base(newKey) {
+		t.Errorf("expected alice's registered key, got %s (%s)", publicKey, keyPath)
+	}
+	alice := loadManifestOrFail(t, service.configPath).FindMember("alice")
+	if len(alice.Keys) != 1 || alice.Keys[0].AgeKey != testRecipientD {
+		t.Errorf("expected the unregistered key for the desktop, got %+v", alice.Keys)
+	}
+
+	// And: several unregistered keys are refused rather than guessed
+	writeLocalKey(t, service.secretsDir, testRecipientB+"x")
+	writeLocalKey(t, service.secretsDir, testRecipientC+"x")
+	err = service.AddMemberKey("carol", "laptop", "")
+	if ExitCode(err) != ExitKey || !containsString(err.Error(), "several private keys") {
+		t.Errorf("expected an ambiguity error, got %v", err)
+	}
+	if _, _, err := service.findExistingKey(nil, "dave"); err == nil {
+		t.Error("expected several keys without a manifest to be ambiguous")
+	}
+}
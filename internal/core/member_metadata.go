@@ -0,0 +1,88 @@
+package core
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"net/mail"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// labelKeyRegex restricts label keys to identifiers such as "team" or "cost-center"
+var labelKeyRegex = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9._/-]*[A-Za-z0-9])?$`)
+
+// validateEmail checks that value is a bare email address, without a display name
+func validateEmail(value string) error {
+	address, err := mail.ParseAddress(value)
+	if err != nil || address.Address != value {
+		return fmt.Errorf("invalid email address %q", value)
+	}
+	return nil
+}
+
+// validateLabel checks a member label's key and value
+func validateLabel(key, value string) error {
+	if !labelKeyRegex.MatchString(key) {
+		return fmt.Errorf("invalid label key %q: use letters, digits, '.', '_', '-' and '/'", key)
+	}
+	if strings.ContainsAny(value, "\n\r") {
+		return fmt.Errorf("label %s: value must be a single line", key)
+	}
+	return nil
+}
+
+// LabelSelector matches members by label, as "team=payments", or "team" for
+// members with any value of the label
+type LabelSelector struct {
+	Key      string
+	Value    string
+	AnyValue bool
+}
+
+// ParseLabelSelector parses a selector given to 'sistry list --label'
+func ParseLabelSelector(selector string) (LabelSelector, error) {
+	key, value, hasValue := strings.Cut(selector, "=")
+	if err := validateLabel(key, value); err != nil {
+		return LabelSelector{}, err
+	}
+	return LabelSelector{Key: key, Value: value, AnyValue: !hasValue}, nil
+}
+
+// Matches reports whether the member has the selected label
+func (l LabelSelector) Matches(member Member) bool {
+	value, ok := member.Labels[l.Key]
+	return ok && (l.AnyValue || value == l.Value)
+}
+
+// withMembers returns a copy of the manifest listing only the members keep accepts
+func (m *Manifest) withMembers(keep func(Member) bool) *Manifest {
+	filtered := *m
+	filtered.Members = Filter(m.Members, keep)
+	return &filtered
+}
+
+// displayMemberMetadata shows the optional details of a member in 'sistry list'
+func displayMemberMetadata(w io.Writer, member Member) {
+	if member.Name != "" || member.Email != "" {
+		contact := strings.TrimSpace(member.Name)
+		if member.Email != "" {
+			contact = strings.TrimSpace(contact + " <" + member.Email + ">")
+		}
+		_, _ = fmt.Fprintf(w, "    Contact: %s\n", contact)
+	}
+	if len(member.GitEmails) > 0 {
+		_, _ = fmt.Fprintf(w, "    Git: %s\n", strings.Join(member.GitEmails, ", "))
+	}
+	if len(member.Labels) > 0 {
+		labels := MapSlice(slices.Sorted(maps.Keys(member.Labels)), func(key string) string { return key + "=" + member.Labels[key] })
+		_, _ = fmt.Fprintf(w, "    Labels: %s\n", strings.Join(labels, ", "))
+	}
+	if member.Approved != nil {
+		_, _ = fmt.Fprintf(w, "    Approved: by %s on %s\n", member.Approved.By, member.Approved.At.Format(DateFormat))
+	}
+	if member.Notes != "" {
+		_, _ = fmt.Fprintf(w, "    Notes: %s\n", strings.ReplaceAll(strings.TrimSpace(member.Notes), "\n", "\n           "))
+	}
+}
@@ -0,0 +1,129 @@
+package core
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+)
+
+// writeMetadataManifest writes a manifest where two members carry metadata
+func writeMetadataManifest(t *testing.T) string {
+	t.Helper()
+	return writeTestFile(t, t.TempDir(), "sopsistry.yaml", `members:
+  - id: jsmith
+    age_key: `+testRecipientA+`
+    name: Jane Smith
+    email: jane@example.com
+    git_emails: [jane@example.com, jsmith@users.noreply.github.com]
+    notes: Payments on-call lead
+    labels:
+      team: payments
+      location: remote
+  - id: bob
+    age_key: `+testRecipientB+`
+    labels:
+      team: platform
+scopes:
+  - name: default
+    patterns: ["*.env"]
+    members: [jsmith, bob]
+`)
+}
+
+func TestManifest_DisplaysMemberMetadata(t *testing.T) {
+	t.Parallel()
+
+	// Given: a member with every kind of metadata
+	service, output, _ := setupScopeTest(t)
+	service.configPath = writeMetadataManifest(t)
+
+	// When: listing the team
+	requireNoError(t, service.List(false), "list should succeed")
+
+	// Then: the metadata is shown under the member
+	for _, want := range []string{
+		"    Contact: Jane Smith <jane@example.com>\n",
+		"    Git: jane@example.com, jsmith@users.noreply.github.com\n",
+		"    Labels: location=remote, team=payments\n",
+		"    Notes: Payments on-call lead\n",
+	} {
+		if !containsString(output.String(), want) {
+			t.Errorf("expected %q in output, got: %s", want, output.String())
+		}
+	}
+}
+
+func TestSopsManager_ListFiltersByLabel(t *testing.T) {
+	t.Parallel()
+
+	// Given: members on two teams
+	service, output, _ := setupScopeTest(t)
+	service.configPath = writeMetadataManifest(t)
+
+	// When: listing the payments team as JSON
+	requireNoError(t, service.List(true, "team=payments", "location"), "list should succeed")
+
+	// Then: only jsmith is listed, with her metadata
+	var doc struct {
+		Result struct {
+			Members []Member `json:"members"`
+		} `json:"result"`
+		Command string `json:"command"`
+	}
+	requireNoError(t, json.Unmarshal(output.Bytes(), &doc), "output should be JSON")
+	if result := doc.Result; doc.Command != "list" || len(result.Members) != 1 || result.Members[0].ID != "jsmith" || result.Members[0].Labels["team"] != "payments" {
+		t.Errorf("expected only jsmith in a list document, got %+v", doc)
+	}
+	requireError(t, service.List(false, "=payments"), "selectors without a key should be refused")
+}
+
+func TestLoadManifest_ValidatesMemberMetadata(t *testing.T) {
+	t.Parallel()
+
+	// Given: invalid emails and labels
+	path := writeTestFile(t, t.TempDir(), "sopsistry.yaml", `members:
+  - id: alice
+    age_key: `+testRecipientA+`
+    email: Alice <alice@example.com>
+    git_emails: [alice@example.com, not-an-email, Alice@example.com]
+    labels:
+      "team name": payments
+scopes:
+  - name: default
+    patterns: ["*.env"]
+    members: [alice]
+`)
+
+	// When: loading it
+	_, err := LoadManifest(path)
+
+	// Then: each problem is reported
+	requireError(t, err, "invalid metadata should be refused")
+	for _, want := range []string{
+		`4:12: member alice: invalid email address "Alice <alice@example.com>"`,
+		`5:37: member alice git_emails: invalid email address "not-an-email"`,
+		`5:51: member alice lists git email "Alice@example.com" more than once`,
+		`7:20: member alice: invalid label key "team name"`,
+	} {
+		if !containsString(err.Error(), want) {
+			t.Errorf("expected %q in error, got: %v", want, err)
+		}
+	}
+}
+
+func TestManifest_SaveKeepsMemberMetadata(t *testing.T) {
+	t.Parallel()
+
+	// Given: a manifest with member metadata
+	manifest := loadManifestOrFail(t, writeMetadataManifest(t))
+
+	// When: saving and reloading it
+	path := filepath.Join(t.TempDir(), "sopsistry.yaml")
+	requireNoError(t, manifest.Save(path), "saving should succeed")
+	saved := loadManifestOrFail(t, path).FindMember("jsmith")
+
+	// Then: nothing is lost
+	if saved.Name != "Jane Smith" || len(saved.GitEmails) != 2 || saved.Notes != "Payments on-call lead" || saved.Labels["location"] != "remote" {
+		t.Errorf("expected metadata to survive saving, got %+v", saved)
+	}
+}
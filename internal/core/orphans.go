@@ -0,0 +1,149 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxScanSize bounds the files read while looking for encrypted files outside all scopes
+const maxScanSize = 10 << 20
+
+// OrphanedFile is a SOPS-encrypted file that no scope matches, such as a file
+// left behind by a pattern edit or one encrypted with plain sops
+type OrphanedFile struct {
+	File              string   `json:"file"`
+	Recipients        []string `json:"recipients"`         // Member IDs the file is encrypted for
+	UnknownRecipients []string `json:"unknown_recipients"` // Age keys not in the manifest
+}
+
+// FindOrphans returns the SOPS-encrypted files below the working directory that no scope matches
+func (p *Planner) FindOrphans(manifest *Manifest) ([]OrphanedFile, error) {
+	_, matches, err := p.scopeMatches(manifest)
+	if err != nil {
+		return nil, err
+	}
+	return p.findOrphans(manifest, matches)
+}
+
+// findOrphans scans for encrypted files whose cwd-relative path is not among
+// matches. Inside a git work tree, only the files git lists are read.
+func (p *Planner) findOrphans(manifest *Manifest, matches map[string][]*Scope) ([]OrphanedFile, error) {
+	git, err := p.discovery(manifest)
+	if err != nil {
+		return nil, err
+	}
+	candidates, listed, err := git.listFiles(p.scanRoot)
+	if err != nil {
+		return nil, err
+	}
+	if !listed {
+		if candidates, err = walkFiles(p.scanRoot); err != nil {
+			return nil, err
+		}
+	}
+
+	var unmatched []string
+	for _, file := range candidates {
+		if _, ok := matches[relativeToWorkDir(file)]; !ok && isEncryptedFile(file) {
+			unmatched = append(unmatched, file)
+		}
+	}
+	if !listed {
+		if unmatched, err = git.filter(unmatched); err != nil {
+			return nil, err
+		}
+	}
+
+	orphans := []OrphanedFile{}
+	for _, file := range unmatched {
+		metadata, err := ReadSOPSMetadata(file)
+		if err != nil {
+			continue
+		}
+		orphans = append(orphans, newOrphanedFile(file, metadata.AgeRecipients, manifest))
+	}
+	return orphans, nil
+}
+
+func newOrphanedFile(file string, keys []string, manifest *Manifest) OrphanedFile {
+	orphan := OrphanedFile{File: file, Recipients: []string{}, UnknownRecipients: []string{}}
+	for _, key := range keys {
+		ids := manifest.MemberIDsForKey(key)
+		if len(ids) == 0 {
+			orphan.UnknownRecipients = append(orphan.UnknownRecipients, key)
+		}
+		orphan.Recipients = append(orphan.Recipients, ids...)
+	}
+	return orphan
+}
+
+// walkFiles returns every regular file below root, skipping .git
+func walkFiles(root string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(root, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if file == root {
+				return err
+			}
+			return nil //nolint:nilerr // Unreadable directories are skipped rather than failing the scan
+		}
+		if entry.IsDir() {
+			if file != root && entry.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.Type().IsRegular() {
+			files = append(files, file)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s for encrypted files: %w", root, err)
+	}
+	return files, nil
+}
+
+// isEncryptedFile reports whether file carries SOPS metadata. Files are
+// checked for the "sops" marker before being parsed.
+func isEncryptedFile(file string) bool {
+	if info, err := os.Lstat(file); err != nil || !info.Mode().IsRegular() || info.Size() > maxScanSize {
+		return false // Files that vanished, links and files too large are not scanned
+	}
+
+	data, err := os.ReadFile(file) //nolint:gosec // Scanning project files for SOPS metadata is expected
+	if err != nil || !bytes.Contains(data, []byte(sopsMetadataKey)) {
+		return false // Unreadable files are not managed files
+	}
+	metadata, err := ParseSOPSMetadata(data, DetectSOPSFormat(file))
+	return err == nil && metadata.IsEncrypted()
+}
+
+// displayOrphans lists encrypted files outside all scopes with their recipients
+func (p *Plan) displayOrphans(r Reporter) {
+	if len(p.Orphans) == 0 {
+		return
+	}
+
+	r.Warnf("⚠️  %d encrypted files match no scope; bring them under one with 'sistry adopt --scope <scope> <file>':", len(p.Orphans))
+	for _, orphan := range p.Orphans {
+		r.Warnf("    %s (%s)", orphan.File, orphan.describeRecipients())
+	}
+	r.Warnf("")
+}
+
+// describeRecipients renders recipients as "alice, age1ql3z7hjy54pw3... (unknown)"
+func (o OrphanedFile) describeRecipients() string {
+	recipients := append([]string{}, o.Recipients...)
+	for _, key := range o.UnknownRecipients {
+		recipients = append(recipients, shortenKey(key)+" (unknown)")
+	}
+	if len(recipients) == 0 {
+		return "no age recipients"
+	}
+	return "recipients: " + strings.Join(recipients, ", ")
+}
@@ -0,0 +1,124 @@
+package core
+
+import (
+	"bytes"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestPlanner_FindsEncryptedFilesOutsideScopes(t *testing.T) {
+	t.Parallel()
+
+	// Given: a managed file, an encrypted file no scope matches and a plaintext one
+	tempDir := t.TempDir()
+	writeTestFile(t, tempDir, "dev.env", sopsDotenvContent(testRecipientA, testRecipientB))
+	writeTestFile(t, tempDir, "legacy/old.env", sopsDotenvContent(testRecipientA, testRecipientD))
+	writeTestFile(t, tempDir, "notes.env", "KEY=value\n")
+
+	manifest := createScopedManifest(tempDir)
+	manifest.Settings.FileDiscovery = string(DiscoveryFilesystem)
+	planner := NewPlanner("sops")
+	planner.scanRoot = tempDir
+
+	// When: planning
+	plan, err := planner.ComputePlan(manifest)
+
+	// Then: only the unmatched encrypted file is reported, with its recipients
+	requireNoError(t, err, "computing plan should succeed")
+	if len(plan.Orphans) != 1 {
+		t.Fatalf("expected one orphaned file, got %+v", plan.Orphans)
+	}
+	orphan := plan.Orphans[0]
+	if orphan.File != filepath.Join(tempDir, "legacy/old.env") {
+		t.Errorf("expected legacy/old.env, got %s", orphan.File)
+	}
+	if !slices.Equal(orphan.Recipients, []string{"alice"}) || !slices.Equal(orphan.UnknownRecipients, []string{testRecipientD}) {
+		t.Errorf("expected alice and one unknown key, got %+v", orphan)
+	}
+
+	var output bytes.Buffer
+	plan.Display(NewWriterReporter(&output))
+	if !containsString(output.String(), "1 encrypted files match no scope") || !containsString(output.String(), "recipients: alice, ") {
+		t.Errorf("expected orphan warning, got: %s", output.String())
+	}
+}
+
+func TestAdoptPattern(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		file string
+		want string
+	}{
+		{file: "legacy/old.env", want: "legacy/old.env"},
+		{file: "./legacy/../old.env", want: "./old.env"},
+		{file: "conf/[prod]*.env", want: `conf/\[prod]\*.env`},
+		{file: "!important.env", want: "./!important.env"},
+	}
+
+	for _, tt := range tests {
+		got, err := adoptPattern(tt.file)
+		requireNoError(t, err, "adoptPattern should succeed for "+tt.file)
+		if got != tt.want {
+			t.Errorf("adoptPattern(%q) = %q, want %q", tt.file, got, tt.want)
+		}
+
+		pattern, err := parseFilePattern(got)
+		requireNoError(t, err, "adopted pattern should parse")
+		if pattern.negated || !pattern.matches(filepath.Clean(tt.file)) {
+			t.Errorf("pattern %q should match %q", got, tt.file)
+		}
+	}
+
+	if _, err := adoptPattern("../elsewhere.env"); err == nil {
+		t.Error("files outside the working directory should be refused")
+	}
+}
+
+func TestSopsManager_AdoptRefusesManagedFiles(t *testing.T) {
+	t.Parallel()
+
+	// Given: a file already matched by the development scope
+	service, _, tempDir := setupScopeTest(t)
+	devFile := writeTestFile(t, tempDir, "dev.env", "KEY=value\n")
+
+	// When: adopting it into another scope
+	err := service.Adopt("production", []string{devFile})
+
+	// Then: adoption is refused
+	requireError(t, err, "adopting a managed file should fail")
+	if !containsString(err.Error(), "already matched by scope development") {
+		t.Errorf("expected already matched error, got: %v", err)
+	}
+
+	// When: adopting into a scope that does not exist
+	err = service.Adopt("missing", []string{devFile})
+
+	// Then: it fails with a manifest error
+	requireError(t, err, "unknown scope should fail")
+	if ExitCode(err) != ExitManifest {
+		t.Errorf("expected manifest exit code, got %d: %v", ExitCode(err), err)
+	}
+}
+
+func TestPlanner_OrphanScanSkipsGitIgnoredFiles(t *testing.T) {
+	t.Parallel()
+
+	// Given: a repository with an encrypted file under the ignored build/ directory and one in legacy/
+	dir := setupGitRepo(t)
+	writeTestFile(t, dir, "build/secret.env", sopsDotenvContent(testRecipientA))
+	writeTestFile(t, dir, "legacy/old.env", sopsDotenvContent(testRecipientA))
+	manifest := createScopedManifest(dir)
+	planner := NewPlanner("sops")
+	planner.scanRoot = dir
+
+	// When: planning with the default discovery
+	plan, err := planner.ComputePlan(manifest)
+
+	// Then: only the file git would track is reported
+	requireNoError(t, err, "computing plan should succeed")
+	if files := MapSlice(plan.Orphans, func(o OrphanedFile) string { return o.File }); !slices.Equal(files, []string{filepath.Join(dir, "legacy/old.env")}) {
+		t.Errorf("expected only legacy/old.env, got %v", files)
+	}
+}
@@ -0,0 +1,129 @@
+package core
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ScopeOverlap is the strategy for files matched by more than one scope
+type ScopeOverlap string
+
+// Overlap strategies, set with settings.scope_overlap in the manifest
+const (
+	OverlapError        ScopeOverlap = "error"         // Refuse to plan until patterns no longer overlap; default
+	OverlapUnion        ScopeOverlap = "union"         // Encrypt for the members of every matching scope
+	OverlapFirst        ScopeOverlap = "first"         // The first matching scope in the manifest wins
+	OverlapMostSpecific ScopeOverlap = "most-specific" // The scope with the most specific matching pattern wins
+)
+
+// ParseScopeOverlap validates a settings.scope_overlap value; empty means error
+func ParseScopeOverlap(value string) (ScopeOverlap, error) {
+	switch strategy := ScopeOverlap(value); strategy {
+	case "":
+		return OverlapError, nil
+	case OverlapError, OverlapUnion, OverlapFirst, OverlapMostSpecific:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("unknown scope overlap %q: must be error, union, first or most-specific", value)
+	}
+}
+
+// governingScope decides which scope governs file, given every scope matching
+// it in manifest order, and explains the decision. For the union strategy it
+// returns a combined scope holding the members of all matching scopes.
+func governingScope(file string, matched []*Scope, strategy ScopeOverlap) (*Scope, string, error) {
+	if len(matched) == 1 {
+		return matched[0], "matched by " + matched[0].Name, nil
+	}
+
+	names := strings.Join(scopeNames(matched), ", ")
+	switch strategy {
+	case OverlapUnion:
+		return unionScope(matched), "union of " + names, nil
+	case OverlapFirst:
+		return matched[0], fmt.Sprintf("first of %s", names), nil
+	case OverlapMostSpecific:
+		scope, pattern := mostSpecificScope(file, matched)
+		return scope, fmt.Sprintf("most specific pattern %s among %s", pattern, names), nil
+	case OverlapError:
+	}
+	return nil, "", fmt.Errorf("%s matches scopes %s", file, names)
+}
+
+func unionScope(scopes []*Scope) *Scope {
+	union := &Scope{Name: strings.Join(scopeNames(scopes), "+")}
+	permanent := NewSet[string]()
+	for _, scope := range scopes {
+		union.Members = appendMissing(union.Members, scope.Members...)
+		union.InheritsMembersFrom = appendMissing(union.InheritsMembersFrom, scope.InheritsMembersFrom...)
+
+		// The latest grant wins, and an entry without one in any scope never expires
+		for _, entry := range scope.Members {
+			until, limited := scope.Until[entry]
+			if !limited {
+				permanent.Add(entry)
+				continue
+			}
+			if union.Until == nil {
+				union.Until = make(map[string]time.Time)
+			}
+			if until.After(union.Until[entry]) {
+				union.Until[entry] = until
+			}
+		}
+	}
+	for _, entry := range permanent.ToSlice() {
+		delete(union.Until, entry)
+	}
+	return union
+}
+
+// appendMissing appends the values not already in list
+func appendMissing(list []string, values ...string) []string {
+	for _, value := range values {
+		if !slices.Contains(list, value) {
+			list = append(list, value)
+		}
+	}
+	return list
+}
+
+// mostSpecificScope picks the scope whose matching pattern has the most
+// literal path segments, then the fewest "**"; ties go to the earlier scope
+func mostSpecificScope(file string, scopes []*Scope) (*Scope, string) {
+	var best *Scope
+	var bestPattern filePattern
+	for _, scope := range scopes {
+		for _, raw := range scope.Patterns {
+			pattern, err := parseFilePattern(raw)
+			if err != nil || pattern.negated || !pattern.matches(file) {
+				continue
+			}
+			if best == nil || pattern.moreSpecificThan(bestPattern) {
+				best, bestPattern = scope, pattern
+			}
+		}
+	}
+	if best == nil {
+		return scopes[0], scopes[0].Patterns[0]
+	}
+	return best, bestPattern.raw
+}
+
+func (p filePattern) moreSpecificThan(other filePattern) bool {
+	literal, otherLiteral := p.literalSegments(), other.literalSegments()
+	if literal != otherLiteral {
+		return literal > otherLiteral
+	}
+	return p.doubleStars() < other.doubleStars()
+}
+
+func (p filePattern) literalSegments() int {
+	return len(Filter(p.segments, func(s string) bool { return !hasWildcard(s) }))
+}
+
+func (p filePattern) doubleStars() int {
+	return len(Filter(p.segments, func(s string) bool { return s == doubleStar }))
+}
@@ -0,0 +1,145 @@
+package core
+
+import (
+	"bytes"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// createOverlappingManifest returns a manifest where config/prod.env matches
+// both a broad "config" scope and a narrower "prod" scope
+func createOverlappingManifest(t *testing.T, strategy ScopeOverlap) (*Manifest, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	writeTestFile(t, dir, "config/dev.env", "KEY=value\n")
+	writeTestFile(t, dir, "config/prod.env", "KEY=value\n")
+
+	manifest := &Manifest{
+		Members: []Member{
+			{ID: "alice", AgeKey: testRecipientA},
+			{ID: "bob", AgeKey: testRecipientB},
+		},
+		Scopes: []Scope{
+			{Name: "config", Patterns: []string{filepath.Join(dir, "config/**")}, Members: []string{"alice"}},
+			{Name: "prod", Patterns: []string{filepath.Join(dir, "config/prod.env")}, Members: []string{"bob"}},
+		},
+		Settings: Settings{FileDiscovery: string(DiscoveryFilesystem), ScopeOverlap: string(strategy)},
+	}
+	return manifest, filepath.Join(dir, "config/prod.env")
+}
+
+func TestPlanner_ScopeOverlapStrategies(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		strategy   ScopeOverlap
+		wantScope  string
+		wantReason string
+		wantIDs    []string
+	}{
+		{strategy: OverlapUnion, wantScope: "config+prod", wantReason: "union of config, prod", wantIDs: []string{"alice", "bob"}},
+		{strategy: OverlapFirst, wantScope: "config", wantReason: "first of config, prod", wantIDs: []string{"alice"}},
+		{strategy: OverlapMostSpecific, wantScope: "prod", wantReason: "among config, prod", wantIDs: []string{"bob"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.strategy), func(t *testing.T) {
+			t.Parallel()
+
+			// Given: a file matched by two scopes
+			manifest, prodFile := createOverlappingManifest(t, tt.strategy)
+
+			// When: planning
+			plan, err := NewPlanner("sops").ComputePlan(manifest)
+
+			// Then: the file is planned once, for the scope the strategy picks
+			requireNoError(t, err, "computing plan should succeed")
+			if len(plan.Actions) != 2 {
+				t.Fatalf("expected one action per file, got %+v", plan.Actions)
+			}
+			action := findActionForFile(t, plan, prodFile)
+			if action.Scope != tt.wantScope {
+				t.Errorf("expected scope %s, got %s", tt.wantScope, action.Scope)
+			}
+			if !containsString(action.Reason, tt.wantReason) {
+				t.Errorf("expected reason containing %q, got %q", tt.wantReason, action.Reason)
+			}
+			if !slices.Equal(action.AddedRecipients, tt.wantIDs) {
+				t.Errorf("expected recipients %v, got %v", tt.wantIDs, action.AddedRecipients)
+			}
+			if !slices.Equal(action.MatchedScopes, []string{"config", "prod"}) {
+				t.Errorf("expected both scopes to be recorded, got %v", action.MatchedScopes)
+			}
+
+			var output bytes.Buffer
+			plan.Display(NewWriterReporter(&output))
+			if !containsString(output.String(), "Scope: "+action.Reason) {
+				t.Errorf("expected plan to explain the scope, got: %s", output.String())
+			}
+		})
+	}
+}
+
+func TestPlanner_ScopeOverlapErrorByDefault(t *testing.T) {
+	t.Parallel()
+
+	// Given: overlapping scopes and no scope_overlap setting
+	manifest, prodFile := createOverlappingManifest(t, "")
+
+	// When: planning
+	_, err := NewPlanner("sops").ComputePlan(manifest)
+
+	// Then: planning fails with a manifest error naming the file and scopes
+	requireError(t, err, "overlapping scopes should be rejected by default")
+	if ExitCode(err) != ExitManifest {
+		t.Errorf("expected manifest exit code, got %d: %v", ExitCode(err), err)
+	}
+	if !containsString(err.Error(), prodFile+" matches scopes config, prod") {
+		t.Errorf("expected overlap details, got: %v", err)
+	}
+}
+
+func TestPlanner_ResolveScopeHonorsOverlap(t *testing.T) {
+	t.Parallel()
+
+	// Given: a file matched by two scopes, resolved by most-specific pattern
+	manifest, prodFile := createOverlappingManifest(t, OverlapMostSpecific)
+
+	// When: resolving the scope for encryption
+	scope, err := NewPlanner("sops").ResolveScope(manifest, prodFile, "")
+
+	// Then: the same scope as in the plan is chosen
+	requireNoError(t, err, "resolving should succeed")
+	if scope.Name != "prod" {
+		t.Errorf("expected prod, got %s", scope.Name)
+	}
+}
+
+func TestLoadManifest_RejectsUnknownScopeOverlap(t *testing.T) {
+	t.Parallel()
+
+	// Given: a manifest with an unknown overlap strategy
+	manifest := `members:
+  - id: alice
+    age_key: ` + testRecipientA + `
+scopes:
+  - name: default
+    patterns: ["*.env"]
+    members: [alice]
+settings:
+  sops_version: ">=3.8.0"
+  scope_overlap: merge
+`
+	path := writeTestFile(t, t.TempDir(), "sopsistry.yaml", manifest)
+
+	// When: loading it
+	_, err := LoadManifest(path)
+
+	// Then: the setting is reported
+	requireError(t, err, "unknown scope_overlap should be rejected")
+	if !containsString(err.Error(), "settings.scope_overlap") {
+		t.Errorf("expected scope_overlap problem, got: %v", err)
+	}
+}
@@ -0,0 +1,272 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Scope patterns follow gitignore semantics, relative to the working directory:
+//
+//   - "*", "?" and "[...]" match within a single path segment
+//   - "**" as a whole segment matches zero or more directories
+//   - a pattern without a "/", such as "*.env", matches at any depth; any
+//     other "/" anchors it, so "./prod.env" only matches at the top
+//   - a pattern naming a directory, such as "secrets" or "secrets/", matches
+//     every file below it; a trailing "/" matches directories only. Unlike
+//     gitignore, a wildcard last segment only matches files, so "secrets/*"
+//     does not reach into secrets/sub/
+//   - "!pattern" re-excludes files matched by earlier patterns; the last
+//     matching pattern wins
+//
+// Files matching any pattern in a scope's exclude list are never included.
+
+const doubleStar = "**"
+
+// filePattern is a parsed scope pattern
+type filePattern struct {
+	raw        string
+	segments   []string
+	negated    bool
+	dirOnly    bool
+	coversDirs bool // Files below a matching directory match too
+}
+
+func parseFilePattern(raw string) (filePattern, error) {
+	p := filePattern{raw: raw}
+	pattern := strings.TrimSpace(raw)
+
+	if strings.HasPrefix(pattern, "!") {
+		p.negated = true
+		pattern = pattern[1:]
+	}
+	if strings.HasSuffix(pattern, "/") {
+		p.dirOnly = true
+	}
+	anchored := strings.Contains(strings.TrimSuffix(filepath.ToSlash(pattern), "/"), "/")
+
+	pattern = path.Clean(filepath.ToSlash(pattern))
+	if pattern == "." || pattern == "/" {
+		return p, fmt.Errorf("pattern %q matches nothing", raw)
+	}
+	p.segments = strings.Split(pattern, "/")
+	if !anchored {
+		p.segments = append([]string{doubleStar}, p.segments...)
+	}
+	p.coversDirs = p.dirOnly || !hasWildcard(p.segments[len(p.segments)-1])
+
+	for _, segment := range p.segments {
+		if _, err := path.Match(segment, ""); err != nil {
+			return p, fmt.Errorf("invalid pattern %q: %w", raw, err)
+		}
+	}
+	return p, nil
+}
+
+// matches reports whether file, or a directory containing it, matches the pattern
+func (p filePattern) matches(file string) bool {
+	segments := splitPath(file)
+	switch {
+	case p.dirOnly:
+		return p.matchesDir(segments[:len(segments)-1]) // The file itself is never a directory
+	case p.coversDirs:
+		return p.matchesDir(segments)
+	}
+	return matchSegments(p.segments, segments)
+}
+
+// matchesDir reports whether the directory, or one of its parents, matches the pattern
+func (p filePattern) matchesDir(segments []string) bool {
+	for n := 1; n <= len(segments); n++ {
+		if matchSegments(p.segments, segments[:n]) {
+			return true
+		}
+	}
+	return false
+}
+
+// mayMatchBelow reports whether files below dir can match the pattern
+func (p filePattern) mayMatchBelow(dir string) bool {
+	segments := splitPath(dir)
+	return matchPrefix(p.segments, segments) || (p.coversDirs && p.matchesDir(segments))
+}
+
+// hasWildcard reports whether a pattern segment matches more than one literal name
+func hasWildcard(segment string) bool {
+	return strings.ContainsAny(segment, `*?[\`)
+}
+
+// baseDir returns the literal directory prefix of the pattern, where a search for matches starts
+func (p filePattern) baseDir() string {
+	var literal []string
+	for _, segment := range p.segments[:len(p.segments)-1] {
+		if segment == doubleStar || hasWildcard(segment) {
+			break
+		}
+		literal = append(literal, segment)
+	}
+
+	switch {
+	case len(literal) == 0 && len(p.segments) > 0 && p.segments[0] == "":
+		return "/"
+	case len(literal) == 0:
+		return "."
+	case literal[0] == "":
+		return "/" + path.Join(literal[1:]...)
+	}
+	return filepath.FromSlash(path.Join(literal...))
+}
+
+func matchSegments(pattern, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+
+	if pattern[0] == doubleStar {
+		for skip := 0; skip <= len(name); skip++ {
+			if matchSegments(pattern[1:], name[skip:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(name) == 0 {
+		return false
+	}
+	if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], name[1:])
+}
+
+// matchPrefix reports whether name could be the leading directories of a path matching pattern
+func matchPrefix(pattern, name []string) bool {
+	switch {
+	case len(name) == 0:
+		return true
+	case len(pattern) == 0:
+		return false
+	case pattern[0] == doubleStar:
+		return true
+	}
+	if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+		return false
+	}
+	return matchPrefix(pattern[1:], name[1:])
+}
+
+func splitPath(file string) []string {
+	return strings.Split(path.Clean(filepath.ToSlash(file)), "/")
+}
+
+// fileMatcher decides whether a file belongs to a scope
+type fileMatcher struct {
+	patterns []filePattern
+	excludes []filePattern
+}
+
+func newFileMatcher(patterns, excludes []string) (*fileMatcher, error) {
+	m := &fileMatcher{}
+	for _, raw := range patterns {
+		p, err := parseFilePattern(raw)
+		if err != nil {
+			return nil, err
+		}
+		m.patterns = append(m.patterns, p)
+	}
+	for _, raw := range excludes {
+		p, err := parseFilePattern(raw)
+		if err != nil {
+			return nil, err
+		}
+		if p.negated {
+			return nil, fmt.Errorf("exclude pattern %q cannot be negated", raw)
+		}
+		m.excludes = append(m.excludes, p)
+	}
+	return m, nil
+}
+
+// matches applies the patterns in order, letting the last match win
+func (m *fileMatcher) matches(file string) bool {
+	included := false
+	for _, p := range m.patterns {
+		if p.matches(file) {
+			included = !p.negated
+		}
+	}
+	if !included {
+		return false
+	}
+	return !slices.ContainsFunc(m.excludes, func(p filePattern) bool { return p.matches(file) })
+}
+
+// findFiles returns every regular file the matcher accepts, sorted. The
+// candidates for each positive pattern come from list, given the pattern's
+// base directory; where list has none, the base directory is walked instead.
+func (m *fileMatcher) findFiles(list func(root string) ([]string, bool, error)) ([]string, error) {
+	seen := NewSet[string]()
+	var files []string
+
+	for _, p := range m.patterns {
+		if p.negated {
+			continue
+		}
+		var candidates []string
+		var listed bool
+		var err error
+		if list != nil {
+			candidates, listed, err = list(p.baseDir())
+		}
+		if err == nil && !listed {
+			candidates, err = p.walkBaseDir()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to search for %s: %w", p.raw, err)
+		}
+
+		for _, file := range candidates {
+			if seen.Contains(file) || !m.matches(file) {
+				continue
+			}
+			if info, err := os.Stat(file); err != nil || info.IsDir() {
+				continue // Broken symlinks and links to directories are not managed files
+			}
+			seen.Add(file)
+			files = append(files, file)
+		}
+	}
+
+	slices.Sort(files)
+	return files, nil
+}
+
+// walkBaseDir returns the files below the pattern's base directory, skipping
+// .git and directories no match can be below
+func (p filePattern) walkBaseDir() ([]string, error) {
+	root := p.baseDir()
+	var files []string
+	err := filepath.WalkDir(root, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil //nolint:nilerr // A pattern whose directory does not exist matches nothing
+			}
+			return err
+		}
+		if entry.IsDir() {
+			if file != root && (entry.Name() == ".git" || !p.mayMatchBelow(file)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		files = append(files, file)
+		return nil
+	})
+	return files, err
+}
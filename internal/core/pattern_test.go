@@ -0,0 +1,85 @@
+package core
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestFileMatcher_Matches(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		file     string
+		patterns []string
+		excludes []string
+		want     bool
+	}{
+		{name: "single star stays in one directory", patterns: []string{"secrets/*.yaml"}, file: "secrets/a/b.yaml", want: false},
+		{name: "double star matches nested files", patterns: []string{"secrets/**/*.yaml"}, file: "secrets/a/b/c.yaml", want: true},
+		{name: "double star matches zero directories", patterns: []string{"secrets/**/*.yaml"}, file: "secrets/c.yaml", want: true},
+		{name: "leading double star matches at any depth", patterns: []string{"**/prod.env"}, file: "deploy/eu/prod.env", want: true},
+		{name: "directory pattern matches files below it", patterns: []string{"secrets"}, file: "secrets/a/b.env", want: true},
+		{name: "trailing slash matches directories only", patterns: []string{"prod.env/"}, file: "prod.env", want: false},
+		{name: "patterns without a slash match at any depth", patterns: []string{"*.env"}, file: "nested/dev.env", want: true},
+		{name: "names without a slash match at any depth", patterns: []string{"prod.env"}, file: "deploy/eu/prod.env", want: true},
+		{name: "patterns with a slash are anchored", patterns: []string{"./*.env"}, file: "nested/dev.env", want: false},
+		{name: "anchored patterns match at the top", patterns: []string{"./*.env"}, file: "dev.env", want: true},
+		{name: "wildcard segments do not cover directories", patterns: []string{"secrets/*"}, file: "secrets/sub/key.env", want: false},
+		{name: "wildcard segments match files", patterns: []string{"secrets/*"}, file: "secrets/key.env", want: true},
+		{name: "trailing slash covers matching directories", patterns: []string{"secrets/*/"}, file: "secrets/sub/key.env", want: true},
+		{name: "negation excludes earlier matches", patterns: []string{"config/**", "!config/defaults.yaml"}, file: "config/defaults.yaml", want: false},
+		{name: "last matching pattern wins", patterns: []string{"!config/a.yaml", "config/*"}, file: "config/a.yaml", want: true},
+		{name: "exclude list always wins", patterns: []string{"config/**/*.yaml"}, excludes: []string{"config/**/defaults.yaml"}, file: "config/eu/defaults.yaml", want: false},
+		{name: "exclude list keeps other files", patterns: []string{"config/**/*.yaml"}, excludes: []string{"config/**/defaults.yaml"}, file: "config/eu/prod.yaml", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			matcher, err := newFileMatcher(tt.patterns, tt.excludes)
+			requireNoError(t, err, "patterns should parse")
+
+			if got := matcher.matches(tt.file); got != tt.want {
+				t.Errorf("matches(%q) with %v excluding %v = %v, want %v", tt.file, tt.patterns, tt.excludes, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileMatcher_FindFiles(t *testing.T) {
+	t.Parallel()
+
+	// Given: a nested config tree with defaults that should stay unmanaged
+	tempDir := t.TempDir()
+	for _, name := range []string{"config/app.yaml", "config/eu/prod.yaml", "config/eu/defaults.yaml", "config/eu/notes.txt", "other/x.yaml"} {
+		writeTestFile(t, tempDir, name, "KEY=value\n")
+	}
+
+	// When: searching with a double-star pattern and an exclude
+	matcher, err := newFileMatcher(
+		[]string{filepath.Join(tempDir, "config/**/*.yaml")},
+		[]string{filepath.Join(tempDir, "config/**/defaults.yaml")},
+	)
+	requireNoError(t, err, "patterns should parse")
+	files, err := matcher.findFiles(nil)
+
+	// Then: only the matching, non-excluded files are found, in sorted order
+	requireNoError(t, err, "finding files should succeed")
+	want := []string{filepath.Join(tempDir, "config/app.yaml"), filepath.Join(tempDir, "config/eu/prod.yaml")}
+	if !slices.Equal(files, want) {
+		t.Errorf("expected %v, got %v", want, files)
+	}
+}
+
+func TestParseFilePattern_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, pattern := range []string{"[", "secrets/[a-", "!", "./"} {
+		if _, err := parseFilePattern(pattern); err == nil {
+			t.Errorf("expected %q to be rejected", pattern)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+)
+
+// PlanFileVersion is the format version written to saved plan files
+const PlanFileVersion = 1
+
+// SavedPlan is a plan serialized together with hashes of everything it was
+// computed from, so it can be applied later only if nothing changed since
+type SavedPlan struct {
+	Created      time.Time         `json:"created"`
+	Plan         *Plan             `json:"plan"`
+	FileHashes   map[string]string `json:"file_hashes"` // SHA-256 of every file the plan touches
+	ManifestHash string            `json:"manifest_hash"`
+	Version      int               `json:"version"`
+}
+
+// NewSavedPlan snapshots the manifest and every file referenced by plan
+func NewSavedPlan(plan *Plan, manifestPath string) (*SavedPlan, error) {
+	manifestHash, err := hashFile(manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash manifest: %w", err)
+	}
+
+	fileHashes := make(map[string]string, len(plan.Actions))
+	for _, action := range plan.Actions {
+		hash, err := hashFile(action.File)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash %s: %w", action.File, err)
+		}
+		fileHashes[action.File] = hash
+	}
+
+	return &SavedPlan{
+		Version:      PlanFileVersion,
+		Created:      time.Now().UTC(),
+		ManifestHash: manifestHash,
+		FileHashes:   fileHashes,
+		Plan:         plan,
+	}, nil
+}
+
+// LoadSavedPlan reads a plan file written by Save
+func LoadSavedPlan(path string) (*SavedPlan, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // Reading user-provided plan file is expected
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan file: %w", err)
+	}
+
+	var saved SavedPlan
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("failed to parse plan file: %w", err)
+	}
+
+	if saved.Version != PlanFileVersion {
+		return nil, fmt.Errorf("unsupported plan file version %d (expected %d)", saved.Version, PlanFileVersion)
+	}
+	if saved.Plan == nil {
+		return nil, fmt.Errorf("plan file %s contains no plan", path)
+	}
+
+	return &saved, nil
+}
+
+// Save writes the plan file as indented JSON
+func (sp *SavedPlan) Save(path string) error {
+	data, err := json.MarshalIndent(sp, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal plan: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), GitignoreFileMode); err != nil {
+		return fmt.Errorf("failed to write plan file: %w", err)
+	}
+
+	return nil
+}
+
+// CheckStale returns an error if the manifest or any planned file changed
+// since the plan was saved
+func (sp *SavedPlan) CheckStale(manifestPath string) error {
+	manifestHash, err := hashFile(manifestPath)
+	if err != nil {
+		return fmt.Errorf("failed to hash manifest: %w", err)
+	}
+	if manifestHash != sp.ManifestHash {
+		return fmt.Errorf("%s changed since the plan was created; run 'sistry plan' again", manifestPath)
+	}
+
+	for _, action := range sp.Plan.Actions {
+		if _, ok := sp.FileHashes[action.File]; !ok {
+			return fmt.Errorf("plan file has no hash for %s", action.File)
+		}
+	}
+
+	files := make([]string, 0, len(sp.FileHashes))
+	for file := range sp.FileHashes {
+		files = append(files, file)
+	}
+	slices.Sort(files)
+
+	for _, file := range files {
+		hash, err := hashFile(file)
+		if err != nil {
+			return fmt.Errorf("%s is no longer readable since the plan was created: %w", file, err)
+		}
+		if hash != sp.FileHashes[file] {
+			return fmt.Errorf("%s changed since the plan was created; run 'sistry plan' again", file)
+		}
+	}
+
+	return nil
+}
+
+// hashFile returns the hex-encoded SHA-256 of the file contents
+func hashFile(path string) (string, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // Hashing files matched by manifest scopes is expected
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
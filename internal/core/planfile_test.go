@@ -0,0 +1,70 @@
+package core
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestSavedPlan_RoundTripAndStaleness(t *testing.T) {
+	t.Parallel()
+
+	// Given: a saved plan for dev.env, which still needs bob added
+	tempDir := t.TempDir()
+	file := writeTestFile(t, tempDir, "dev.env", sopsDotenvContent(testRecipientA))
+	manifest := createScopedManifest(tempDir)
+	manifestPath := filepath.Join(tempDir, "sopsistry.yaml")
+	requireNoError(t, manifest.Save(manifestPath), "saving manifest should succeed")
+
+	plan, err := NewPlanner("sops").ComputePlan(manifest)
+	requireNoError(t, err, "computing plan should succeed")
+	saved, err := NewSavedPlan(plan, manifestPath)
+	requireNoError(t, err, "snapshotting plan should succeed")
+	planPath := filepath.Join(tempDir, "plan.json")
+	requireNoError(t, saved.Save(planPath), "saving plan should succeed")
+
+	// When: loading it back with nothing changed
+	loaded, err := LoadSavedPlan(planPath)
+
+	// Then: the same actions are restored and the plan is not stale
+	requireNoError(t, err, "loading plan should succeed")
+	if action := findActionForFile(t, loaded.Plan, file); action.Type != ActionReencrypt {
+		t.Errorf("expected re-encrypt action, got %s", action.Type)
+	}
+	requireNoError(t, loaded.CheckStale(manifestPath), "unchanged plan should not be stale")
+
+	// When: a planned file changes after planning
+	writeTestFile(t, tempDir, "dev.env", sopsDotenvContent(testRecipientA, testRecipientB))
+
+	// Then: the plan is refused
+	err = loaded.CheckStale(manifestPath)
+	requireError(t, err, "plan should be stale after a planned file changed")
+	if !containsString(err.Error(), "dev.env") {
+		t.Errorf("expected error to name the changed file, got: %v", err)
+	}
+
+	// When: the manifest changes after planning
+	writeTestFile(t, tempDir, "dev.env", sopsDotenvContent(testRecipientA))
+	manifest.Scopes[0].Members = []string{"alice"}
+	requireNoError(t, manifest.Save(manifestPath), "saving manifest should succeed")
+
+	// Then: the plan is refused
+	err = loaded.CheckStale(manifestPath)
+	requireError(t, err, "plan should be stale after the manifest changed")
+	if !containsString(err.Error(), "sopsistry.yaml") {
+		t.Errorf("expected error to name the manifest, got: %v", err)
+	}
+}
+
+func TestLoadSavedPlan_UnsupportedVersion(t *testing.T) {
+	t.Parallel()
+
+	// Given: a plan file written by an incompatible version
+	tempDir := t.TempDir()
+	planPath := writeTestFile(t, tempDir, "plan.json", `{"version": 99, "plan": {"actions": []}}`)
+
+	// When: loading it
+	_, err := LoadSavedPlan(planPath)
+
+	// Then: it is refused
+	requireError(t, err, "unknown plan file version should be refused")
+}
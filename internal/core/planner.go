@@ -2,9 +2,12 @@ package core
 
 import (
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 )
 
 // ActionType represents the type of action to be performed
@@ -15,124 +18,400 @@ const (
 	ActionEncrypt   ActionType = "encrypt"    // Encrypt a new file
 	ActionReencrypt ActionType = "re-encrypt" // Re-encrypt existing file with new keys
 	ActionSkip      ActionType = "skip"       // Skip file (no members in scope)
+	ActionNoop      ActionType = "noop"       // File already encrypted for exactly the scope's recipients
 )
 
+// ChangesFile reports whether executing an action of this type modifies the file
+func (t ActionType) ChangesFile() bool {
+	return t == ActionEncrypt || t == ActionReencrypt
+}
+
 // Action represents a single planned action
 type Action struct { //nolint:govet // Field alignment optimization not critical for this struct
-	Recipients  []string   `json:"recipients"`
-	File        string     `json:"file"`
-	Scope       string     `json:"scope"`
-	Description string     `json:"description"`
-	Type        ActionType `json:"type"`
+	Recipients        []string          `json:"recipients"`
+	AddedRecipients   []string          `json:"added_recipients"`            // Member IDs gaining access
+	RemovedRecipients []string          `json:"removed_recipients"`          // Member IDs losing access
+	UnknownRecipients []string          `json:"unknown_recipients"`          // Current age keys not in the manifest
+	AddedKeys         []string          `json:"added_keys,omitempty"`        // Keys of current recipients' other devices, as "alice/desktop"
+	ExpiredAccess     []string          `json:"expired_access,omitempty"`    // Removed recipients whose time-limited grant has ended
+	SuspendedMembers  []string          `json:"suspended_members,omitempty"` // Scope members left out while suspended
+	File              string            `json:"file"`
+	Scope             string            `json:"scope"`
+	Description       string            `json:"description"`
+	Type              ActionType        `json:"type"`
+	Reason            string            `json:"reason,omitempty"`            // Why Scope governs the file
+	MatchedScopes     []string          `json:"matched_scopes,omitempty"`    // Every matching scope, when there is more than one
+	InheritedMembers  map[string]string `json:"inherited_members,omitempty"` // Member ID -> scope it is inherited from
+	Untracked         bool              `json:"untracked,omitempty"`         // File matched a pattern but is not tracked by git
 }
 
 // Plan contains all planned actions
 type Plan struct {
-	Actions []Action `json:"actions"`
+	Actions []Action       `json:"actions"`
+	Orphans []OrphanedFile `json:"orphans,omitempty"` // Encrypted files no scope matches
+	Ignored []string       `json:"ignored,omitempty"` // Files matching a scope that git ignores, and so are not managed
+}
+
+// HasChanges reports whether any action in the plan modifies a file
+func (p *Plan) HasChanges() bool {
+	return Contains(p.Actions, func(a Action) bool { return a.Type.ChangesFile() })
+}
+
+// ChangeCount returns the number of actions that modify a file
+func (p *Plan) ChangeCount() int {
+	return len(Filter(p.Actions, func(a Action) bool { return a.Type.ChangesFile() }))
+}
+
+// UpToDateCount returns the number of files already encrypted for their scope
+func (p *Plan) UpToDateCount() int {
+	return len(Filter(p.Actions, func(a Action) bool { return a.Type == ActionNoop }))
+}
+
+// Summary returns a one-line count of planned actions by type
+func (p *Plan) Summary() string {
+	count := func(t ActionType) int {
+		return len(Filter(p.Actions, func(a Action) bool { return a.Type == t }))
+	}
+
+	summary := fmt.Sprintf("%d to encrypt, %d to re-encrypt, %d up to date",
+		count(ActionEncrypt), count(ActionReencrypt), count(ActionNoop))
+	if skipped := count(ActionSkip); skipped > 0 {
+		summary += fmt.Sprintf(", %d skipped", skipped)
+	}
+	return summary
+}
+
+// Result summarizes the plan for --json output; planFile is set when the plan was saved
+func (p *Plan) Result(planFile string) PlanResult {
+	return PlanResult{
+		Actions:  p.Actions,
+		Orphans:  append([]OrphanedFile{}, p.Orphans...),
+		Ignored:  p.Ignored,
+		PlanFile: planFile,
+		Changes:  p.ChangeCount(),
+		UpToDate: p.UpToDateCount(),
+	}
 }
 
 // Planner computes execution plans for SOPS operations
 type Planner struct {
-	sopsPath string
+	git            *gitDiscovery
+	matched        *scopeMatchResult // Scope matches of the last manifest planned
+	sopsPath       string
+	scanRoot       string // Directory searched for encrypted files outside all scopes
+	rotateDataKeys bool
+	now            time.Time // When the plan is computed; grants expire relative to it
 }
 
 // NewPlanner creates a new planner instance
 func NewPlanner(sopsPath string) *Planner {
 	return &Planner{
 		sopsPath: sopsPath,
+		scanRoot: ".",
+		now:      time.Now(),
 	}
 }
 
-// ComputePlan calculates what actions need to be taken
+// WithRotateDataKeys makes the planner re-encrypt files even when their
+// recipients are already up to date, so that every file gets a fresh data key
+func (p *Planner) WithRotateDataKeys(rotate bool) *Planner { //nolint:revive // rotate is a legitimate CLI flag parameter
+	p.rotateDataKeys = rotate
+	return p
+}
+
+// ComputePlan calculates what actions need to be taken. A file matched by
+// more than one scope is resolved with the manifest's scope_overlap strategy.
 func (p *Planner) ComputePlan(manifest *Manifest) (*Plan, error) {
+	strategy, err := ParseScopeOverlap(manifest.Settings.ScopeOverlap)
+	if err != nil {
+		return nil, NewManifestError("validate", "settings", err)
+	}
+
+	files, matches, err := p.scopeMatches(manifest)
+	if err != nil {
+		return nil, err
+	}
+
 	plan := &Plan{Actions: []Action{}}
+	if p.git != nil {
+		plan.Ignored = p.git.ignoredFiles()
+	}
+	var overlaps []string
+	for _, file := range files {
+		matched := matches[relativeToWorkDir(file)]
+		scope, reason, err := governingScope(file, matched, strategy)
+		if err != nil {
+			overlaps = append(overlaps, err.Error())
+			continue
+		}
 
-	for _, scope := range manifest.Scopes {
-		actions, err := p.planScopeActions(scope, manifest)
+		action, err := p.planFileAction(file, scope, manifest)
 		if err != nil {
 			return nil, err
 		}
-		plan.Actions = append(plan.Actions, actions...)
+		action.Reason = reason
+		if len(matched) > 1 {
+			action.MatchedScopes = scopeNames(matched)
+		}
+		plan.Actions = append(plan.Actions, action)
+	}
+
+	if len(overlaps) > 0 {
+		return nil, NewManifestError("validate", "scopes", fmt.Errorf(
+			"%d files match more than one scope; narrow the patterns or set settings.scope_overlap to union, first or most-specific:\n  %s",
+			len(overlaps), strings.Join(overlaps, "\n  ")))
+	}
+
+	if plan.Orphans, err = p.findOrphans(manifest, matches); err != nil {
+		return nil, err
 	}
 
 	return plan, nil
 }
 
-func (p *Planner) planScopeActions(scope Scope, manifest *Manifest) ([]Action, error) {
-	files, err := p.findMatchingFiles(scope.Patterns)
-	if err != nil {
-		return nil, fmt.Errorf("failed to find files for scope %s: %w", scope.Name, err)
+// scopeMatchResult holds what scopeMatches found for manifest
+type scopeMatchResult struct {
+	manifest *Manifest
+	files    []string
+	matches  map[string][]*Scope
+}
+
+// scopeMatches returns every managed file in discovery order, together with
+// the scopes matching each file (keyed by cwd-relative path) in manifest order.
+// Files are matched once per manifest and planner.
+func (p *Planner) scopeMatches(manifest *Manifest) ([]string, map[string][]*Scope, error) {
+	if p.matched != nil && p.matched.manifest == manifest {
+		return p.matched.files, p.matched.matches, nil
 	}
 
-	members, err := manifest.GetScopeMembers(scope.Name)
+	var files []string
+	matches := make(map[string][]*Scope)
+
+	for i := range manifest.Scopes {
+		scope := &manifest.Scopes[i]
+		scopeFiles, err := p.findScopeFiles(manifest, *scope)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to find files for scope %s: %w", scope.Name, err)
+		}
+		for _, file := range scopeFiles {
+			key := relativeToWorkDir(file)
+			if _, seen := matches[key]; !seen {
+				files = append(files, file)
+			}
+			matches[key] = append(matches[key], scope)
+		}
+	}
+
+	p.matched = &scopeMatchResult{manifest: manifest, files: files, matches: matches}
+	return files, matches, nil
+}
+
+func (p *Planner) planFileAction(file string, scope *Scope, manifest *Manifest) (Action, error) {
+	members, inherited, err := manifest.ScopeMembership(scope, p.now)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get members for scope %s: %w", scope.Name, err)
+		return Action{}, fmt.Errorf("failed to get members for scope %s: %w", scope.Name, err)
 	}
 
 	if len(members) == 0 {
-		return p.createSkipActions(files, scope.Name), nil
+		return p.createSkipAction(file, scope.Name, "No members in scope"), nil
 	}
 
-	recipients := p.extractAgeKeys(members)
-	return p.createFileActions(files, scope.Name, recipients), nil
+	action := p.createFileAction(file, scope.Name, members, manifest)
+	if len(inherited) > 0 {
+		action.InheritedMembers = inherited
+	}
+	markSuspendedMembers(&action, manifest.SuspendedMembers(scope, p.now))
+	markExpiredAccess(&action, manifest.expiredGrantMembers(scope, p.now))
+	return action, nil
 }
 
-func (p *Planner) createSkipActions(files []string, scopeName string) []Action {
-	return MapSlice(files, func(file string) Action {
-		return Action{
-			Type:        ActionSkip,
-			File:        file,
-			Scope:       scopeName,
-			Recipients:  []string{},
-			Description: "No members in scope",
-		}
-	})
+// markSuspendedMembers records the scope's suspended members, and explains a
+// re-encryption that removes them
+func markSuspendedMembers(action *Action, suspended []string) {
+	if len(suspended) == 0 {
+		return
+	}
+	action.SuspendedMembers = suspended
+	if action.Type == ActionReencrypt && Contains(action.RemovedRecipients, func(id string) bool { return slices.Contains(suspended, id) }) {
+		action.Description = "Member suspended, re-encrypt to remove"
+	}
+}
+
+// markExpiredAccess explains a re-encryption that revokes ended grants, so a
+// scheduled apply can be recognized as such
+func markExpiredAccess(action *Action, expired *Set[string]) {
+	action.ExpiredAccess = Filter(action.RemovedRecipients, expired.Contains)
+	if len(action.ExpiredAccess) == 0 {
+		action.ExpiredAccess = nil
+		return
+	}
+	if action.Type == ActionReencrypt {
+		action.Description = "Access expired, re-encrypt to remove"
+	}
 }
 
+func (p *Planner) createSkipAction(file, scopeName, reason string) Action {
+	return Action{
+		Type:        ActionSkip,
+		File:        file,
+		Scope:       scopeName,
+		Recipients:  []string{},
+		Description: reason,
+		Untracked:   p.git.isUntracked(file),
+	}
+}
+
+// extractAgeKeys returns the keys of every device of every member
 func (p *Planner) extractAgeKeys(members []Member) []string {
-	return MapSlice(members, func(m Member) string { return m.AgeKey })
+	var keys []string
+	for _, member := range members {
+		keys = append(keys, member.PublicKeys()...)
+	}
+	return keys
+}
+
+func (p *Planner) createFileAction(file, scopeName string, members []Member, manifest *Manifest) Action {
+	memberIDs := MapSlice(members, func(m Member) string { return m.ID })
+	action := Action{
+		Type:              ActionEncrypt,
+		File:              file,
+		Scope:             scopeName,
+		Recipients:        p.extractAgeKeys(members),
+		AddedRecipients:   memberIDs,
+		RemovedRecipients: []string{},
+		UnknownRecipients: []string{},
+		Description:       "Encrypt with current team",
+		Untracked:         p.git.isUntracked(file),
+	}
+
+	// A file whose metadata cannot be read may well be encrypted already, so it
+	// is skipped rather than encrypted a second time
+	metadata, err := ReadSOPSMetadata(file)
+	switch {
+	case err != nil:
+		return p.createSkipAction(file, scopeName, fmt.Sprintf("Cannot read SOPS metadata, fix the file first: %v", err))
+	case metadata.IsEncrypted():
+		p.classifyEncryptedFile(&action, metadata.AgeRecipients, memberIDs, manifest)
+	}
+
+	return action
 }
 
-func (p *Planner) createFileActions(files []string, scopeName string, recipients []string) []Action {
-	return MapSlice(files, func(file string) Action {
-		actionType := ActionEncrypt
-		description := "Encrypt with current team"
+// classifyEncryptedFile decides whether an already encrypted file needs re-encryption
+func (p *Planner) classifyEncryptedFile(action *Action, currentKeys, targetIDs []string, manifest *Manifest) {
+	p.diffRecipients(action, currentKeys, targetIDs, manifest)
+
+	switch {
+	case !sameKeys(currentKeys, action.Recipients):
+		action.Type = ActionReencrypt
+		action.Description = "Re-encrypt with updated team"
+	case p.rotateDataKeys:
+		action.Type = ActionReencrypt
+		action.Description = "Re-encrypt with fresh data key"
+	default:
+		action.Type = ActionNoop
+		action.Description = "Up to date"
+	}
+}
 
-		if p.isSOPSFile(file) {
-			actionType = ActionReencrypt
-			description = "Re-encrypt with updated team"
+// sameKeys reports whether both lists contain the same set of age keys
+func sameKeys(a, b []string) bool {
+	setA, setB := NewSet(a...), NewSet(b...)
+	return setA.Size() == setB.Size() && setA.Intersection(setB).Size() == setA.Size()
+}
+
+// diffRecipients records which members gain and lose access compared to the
+// age recipients the file is currently encrypted for
+func (p *Planner) diffRecipients(action *Action, currentKeys, targetIDs []string, manifest *Manifest) {
+	current := NewSet[string]()
+	for _, key := range currentKeys {
+		ids := manifest.MemberIDsForKey(key)
+		if len(ids) == 0 {
+			action.UnknownRecipients = append(action.UnknownRecipients, key)
 		}
+		for _, id := range ids {
+			current.Add(id)
+		}
+	}
 
-		return Action{
-			Type:        actionType,
-			File:        file,
-			Scope:       scopeName,
-			Recipients:  recipients,
-			Description: description,
+	target := NewSet(targetIDs...)
+	removed := Filter(current.ToSlice(), func(id string) bool { return !target.Contains(id) })
+	slices.Sort(removed)
+
+	action.AddedRecipients = Filter(targetIDs, func(id string) bool { return !current.Contains(id) })
+	action.RemovedRecipients = removed
+
+	// Members who can already decrypt the file may have registered more devices since
+	encrypted := NewSet(currentKeys...)
+	for _, id := range targetIDs {
+		member := manifest.FindMember(id)
+		if member == nil || !current.Contains(id) {
+			continue
+		}
+		for _, key := range member.AgeKeys() {
+			if !encrypted.Contains(key.AgeKey) {
+				action.AddedKeys = append(action.AddedKeys, keyName(id, key.Label))
+			}
 		}
-	})
+	}
 }
 
 // Display shows the plan in human-readable format
-func (p *Plan) Display(noColor bool) {
-	if len(p.Actions) == 0 {
-		fmt.Println("No changes planned")
+func (p *Plan) Display(r Reporter) {
+	upToDate := p.UpToDateCount()
+	p.displayIgnored(r)
+	p.displayUntracked(r)
+	p.displayOrphans(r)
+
+	if !p.HasChanges() {
+		r.Infof("No changes planned")
+		if upToDate > 0 {
+			r.Infof("%d files up to date", upToDate)
+		}
+		return
+	}
+
+	p.displayHeader(r, upToDate)
+	p.displayActions(r)
+	p.displayLegend(r)
+}
+
+// displayUntracked warns about matched files git does not track, which are
+// often scratch copies such as prod.env.bak that should not be managed
+func (p *Plan) displayUntracked(r Reporter) {
+	untracked := Filter(p.Actions, func(a Action) bool { return a.Untracked })
+	if len(untracked) == 0 {
 		return
 	}
 
-	p.displayHeader()
-	p.displayActions(noColor)
-	p.displayLegend()
+	r.Warnf("⚠️  %d matched files are not tracked by git; add them to git or exclude them from their scope:", len(untracked))
+	for _, action := range untracked {
+		r.Warnf("    %s (scope %s)", action.File, action.Scope)
+	}
+	r.Warnf("")
 }
 
-func (p *Plan) displayHeader() {
-	fmt.Printf("Planned actions (%d files):\n\n", len(p.Actions))
+// displayIgnored lists, in verbose mode, matched files left out because git ignores them
+func (p *Plan) displayIgnored(r Reporter) {
+	if len(p.Ignored) == 0 {
+		return
+	}
+
+	r.Verbosef("%d matched files are ignored by git and not managed (set settings.file_discovery to filesystem to include them):", len(p.Ignored))
+	for _, file := range p.Ignored {
+		r.Verbosef("    %s", file)
+	}
+	r.Verbosef("")
+}
+
+func (p *Plan) displayHeader(r Reporter, upToDate int) {
+	r.Infof("Planned actions (%d files, %d up to date):\n", p.ChangeCount(), upToDate)
 }
 
-func (p *Plan) displayActions(noColor bool) {
+func (p *Plan) displayActions(r Reporter) {
 	for _, action := range p.Actions {
-		prefix := p.getActionPrefix(action.Type, noColor)
-		p.displayAction(&action, prefix)
+		prefix := p.getActionPrefix(action.Type, !r.ColorEnabled())
+		p.displayAction(r, &action, prefix)
 	}
 }
 
@@ -155,6 +434,8 @@ func (a ActionDisplay) ColoredFormat() string {
 		return "\033[33m~\033[0m" // Yellow ~
 	case ActionSkip:
 		return "\033[90m-\033[0m" // Gray -
+	case ActionNoop:
+		return "\033[90m=\033[0m" // Gray =
 	default:
 		return "?"
 	}
@@ -169,6 +450,8 @@ func (a ActionDisplay) PlainFormat() string {
 		return "~"
 	case ActionSkip:
 		return "-"
+	case ActionNoop:
+		return "="
 	default:
 		return "?"
 	}
@@ -182,79 +465,190 @@ func (p *Plan) getActionPrefix(actionType ActionType, noColor bool) string { //n
 	return display.ColoredFormat()
 }
 
-func (p *Plan) displayAction(action *Action, prefix string) {
-	fmt.Printf("%s %s (%s): %s\n",
-		prefix, action.File, action.Scope, action.Description)
+func (p *Plan) displayAction(r Reporter, action *Action, prefix string) {
+	r.Infof("%s %s (%s): %s", prefix, action.File, action.Scope, action.Description)
+
+	if len(action.MatchedScopes) > 1 {
+		r.Infof("  Scope: %s", action.Reason)
+	} else if action.Reason != "" {
+		r.Verbosef("  Scope: %s", action.Reason)
+	}
+
+	if action.Type.ChangesFile() && len(action.Recipients) > 0 {
+		r.Infof("  Recipients: %d keys", len(action.Recipients))
+	}
+
+	if inherited := formatInheritedMembers(action); inherited != "" {
+		r.Infof("  Inherited: %s", inherited)
+	}
 
-	if action.Type != ActionSkip && len(action.Recipients) > 0 {
-		fmt.Printf("  Recipients: %d keys\n", len(action.Recipients))
+	if len(action.SuspendedMembers) > 0 {
+		r.Infof("  Suspended: %s", strings.Join(action.SuspendedMembers, ", "))
+	}
+
+	if changes := formatRecipientChanges(action, !r.ColorEnabled()); changes != "" {
+		r.Infof("  Access: %s", changes)
 	}
 }
 
-func (p *Plan) displayLegend() {
-	fmt.Printf("\nLegend:\n")
-	fmt.Printf("  + = new encryption\n")
-	fmt.Printf("  ~ = re-encryption\n")
-	fmt.Printf("  - = skipped\n")
+// formatInheritedMembers renders inherited members as "bob (from production)"
+func formatInheritedMembers(action *Action) string {
+	ids := slices.Sorted(maps.Keys(action.InheritedMembers))
+	return strings.Join(MapSlice(ids, func(id string) string {
+		return fmt.Sprintf("%s (from %s)", id, action.InheritedMembers[id])
+	}), ", ")
 }
 
-// findMatchingFiles finds all files matching the given patterns
-func (p *Planner) findMatchingFiles(patterns []string) ([]string, error) {
-	var files []string
-	seen := make(map[string]bool)
+// formatRecipientChanges renders gained and lost access as "+alice -bob",
+// with recipients unknown to the manifest flagged in red
+func formatRecipientChanges(action *Action, noColor bool) string { //nolint:revive // noColor is a legitimate CLI flag parameter
+	changes := make([]string, 0, len(action.AddedRecipients)+len(action.AddedKeys)+len(action.RemovedRecipients)+len(action.UnknownRecipients))
 
-	for _, pattern := range patterns {
-		patternFiles, err := p.findFilesForPattern(pattern, seen)
-		if err != nil {
-			return nil, err
-		}
-		files = append(files, patternFiles...)
+	for _, id := range append(slices.Clone(action.AddedRecipients), action.AddedKeys...) {
+		changes = append(changes, colorize("+"+id, ansiGreen, noColor))
+	}
+	for _, id := range action.RemovedRecipients {
+		changes = append(changes, colorize("-"+id, ansiRed, noColor))
+	}
+	for _, key := range action.UnknownRecipients {
+		changes = append(changes, colorize("-"+shortenKey(key)+" (unknown)", ansiRed, noColor))
 	}
 
-	return files, nil
+	return strings.Join(changes, " ")
 }
 
-func (p *Planner) findFilesForPattern(pattern string, seen map[string]bool) ([]string, error) {
-	matches, err := filepath.Glob(pattern)
+// ANSI colour codes used in plan output
+const (
+	ansiGreen = "\033[32m"
+	ansiRed   = "\033[31m"
+	ansiReset = "\033[0m"
+
+	shortKeyLength = 16
+)
+
+func colorize(text, color string, noColor bool) string { //nolint:revive // noColor is a legitimate CLI flag parameter
+	if noColor {
+		return text
+	}
+	return color + text + ansiReset
+}
+
+// shortenKey abbreviates an age key for display
+func shortenKey(key string) string {
+	if len(key) <= shortKeyLength {
+		return key
+	}
+	return key[:shortKeyLength] + "..."
+}
+
+func (p *Plan) displayLegend(r Reporter) {
+	r.Infof("\nLegend:")
+	r.Infof("  + = new encryption")
+	r.Infof("  ~ = re-encryption")
+	r.Infof("  - = skipped")
+	r.Infof("  = = up to date")
+}
+
+// ResolveScope determines which scope governs file, using the same pattern
+// matching as ComputePlan. An explicit scopeName overrides pattern matching,
+// but must not contradict a scope the file already matches.
+func (p *Planner) ResolveScope(manifest *Manifest, file, scopeName string) (*Scope, error) {
+	matched, err := p.matchingScopes(manifest, file)
 	if err != nil {
-		return nil, fmt.Errorf("invalid pattern %s: %w", pattern, err)
+		return nil, err
 	}
 
-	files := Filter(matches, func(match string) bool {
-		return p.shouldIncludeFile(match, seen)
-	})
+	if scopeName != "" {
+		return p.resolveExplicitScope(manifest, file, scopeName, matched)
+	}
 
-	// Mark filtered files as seen
-	for _, file := range files {
-		seen[file] = true
+	if len(matched) == 0 {
+		return nil, fmt.Errorf("%s does not match any scope pattern (use --scope to choose one explicitly)", file)
 	}
 
-	return files, nil
+	strategy, err := ParseScopeOverlap(manifest.Settings.ScopeOverlap)
+	if err != nil {
+		return nil, NewManifestError("validate", "settings", err)
+	}
+	scope, _, err := governingScope(file, matched, strategy)
+	if err != nil {
+		return nil, fmt.Errorf("%s matches multiple scopes (%s); use --scope to choose one or set settings.scope_overlap",
+			file, strings.Join(scopeNames(matched), ", "))
+	}
+	return scope, nil
 }
 
-func (p *Planner) shouldIncludeFile(filePath string, seen map[string]bool) bool {
-	if seen[filePath] {
-		return false
+func (p *Planner) resolveExplicitScope(manifest *Manifest, file, scopeName string, matched []*Scope) (*Scope, error) {
+	scope := manifest.FindScope(scopeName)
+	if scope == nil {
+		return nil, fmt.Errorf("scope %s not found", scopeName)
 	}
 
-	if info, err := os.Stat(filePath); err == nil && info.IsDir() {
-		return false
+	if len(matched) > 0 && !slices.Contains(matched, scope) {
+		return nil, fmt.Errorf("%s matches scope %s, not %s; 'sistry apply' would re-encrypt it for that scope",
+			file, strings.Join(scopeNames(matched), ", "), scopeName)
 	}
 
-	return true
+	return scope, nil
 }
 
-// isSOPSFile checks if a file is already encrypted with SOPS
-func (p *Planner) isSOPSFile(file string) bool {
-	data, err := os.ReadFile(file) //nolint:gosec // Reading project files for analysis is expected
+// matchingScopes returns every scope whose patterns match file
+func (p *Planner) matchingScopes(manifest *Manifest, file string) ([]*Scope, error) {
+	_, matches, err := p.scopeMatches(manifest)
 	if err != nil {
-		return false
+		return nil, err
 	}
+	return matches[relativeToWorkDir(file)], nil
+}
 
-	content := string(data)
+// relativeToWorkDir converts file into the cwd-relative form produced by pattern globbing
+func relativeToWorkDir(file string) string {
+	if !filepath.IsAbs(file) {
+		return filepath.Clean(file)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return filepath.Clean(file)
+	}
+	if rel, err := filepath.Rel(wd, file); err == nil {
+		return rel
+	}
+	return filepath.Clean(file)
+}
+
+func scopeNames(scopes []*Scope) []string {
+	return MapSlice(scopes, func(s *Scope) string { return s.Name })
+}
+
+// findScopeFiles finds all files matching the scope's patterns and not its
+// excludes, leaving out files ignored by git unless discovery is "filesystem".
+// Inside a work tree, the candidates are the files git lists.
+func (p *Planner) findScopeFiles(manifest *Manifest, scope Scope) ([]string, error) {
+	matcher, err := newFileMatcher(scope.Patterns, scope.Exclude)
+	if err != nil {
+		return nil, err
+	}
+	git, err := p.discovery(manifest)
+	if err != nil {
+		return nil, err
+	}
 
-	return strings.Contains(content, "sops:") ||
-		strings.Contains(content, "\"sops\"") ||
-		strings.Contains(content, "lastmodified") ||
-		strings.Contains(content, "mac")
+	files, err := matcher.findFiles(git.candidates)
+	if err != nil {
+		return nil, err
+	}
+	return git.filter(files)
+}
+
+// discovery returns the git filter for the manifest's file_discovery mode,
+// shared across scopes so each work tree is only looked up once
+func (p *Planner) discovery(manifest *Manifest) (*gitDiscovery, error) {
+	if p.git == nil {
+		mode, err := ParseFileDiscovery(manifest.Settings.FileDiscovery)
+		if err != nil {
+			return nil, err
+		}
+		p.git = newGitDiscovery(mode)
+	}
+	return p.git, nil
 }
@@ -0,0 +1,252 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPlanner_ResolveScope(t *testing.T) {
+	t.Parallel()
+
+	// Given: a manifest with development and production scopes
+	tempDir := t.TempDir()
+	devFile := writeTestFile(t, tempDir, "dev.env", "KEY=value\n")
+	prodFile := writeTestFile(t, tempDir, "prod.env", "KEY=value\n")
+	otherFile := writeTestFile(t, tempDir, "other.txt", "data\n")
+	manifest := createScopedManifest(tempDir)
+	planner := NewPlanner("sops")
+
+	// When: resolving a file matched by a single scope
+	scope, err := planner.ResolveScope(manifest, prodFile, "")
+
+	// Then: that scope is chosen
+	requireNoError(t, err, "resolving prod file should succeed")
+	if scope.Name != "production" {
+		t.Errorf("expected production scope, got %s", scope.Name)
+	}
+
+	// When: resolving a file matched by no scope
+	_, err = planner.ResolveScope(manifest, otherFile, "")
+
+	// Then: it is refused
+	requireError(t, err, "file outside all scopes should be refused")
+
+	// When: choosing a scope explicitly for a file outside all scopes
+	scope, err = planner.ResolveScope(manifest, otherFile, "development")
+
+	// Then: the explicit scope is used
+	requireNoError(t, err, "explicit scope should be accepted")
+	if scope.Name != "development" {
+		t.Errorf("expected development scope, got %s", scope.Name)
+	}
+
+	// When: choosing a scope that contradicts the matching one
+	_, err = planner.ResolveScope(manifest, devFile, "production")
+
+	// Then: it is refused
+	requireError(t, err, "contradicting explicit scope should be refused")
+
+	// When: naming a scope that does not exist
+	_, err = planner.ResolveScope(manifest, devFile, "staging")
+
+	// Then: it is refused
+	requireError(t, err, "unknown scope should be refused")
+}
+
+func TestPlanner_ResolveScope_MultipleMatches(t *testing.T) {
+	t.Parallel()
+
+	// Given: two scopes whose patterns both match the same file
+	tempDir := t.TempDir()
+	file := writeTestFile(t, tempDir, "prod.env", "KEY=value\n")
+	manifest := createScopedManifest(tempDir)
+	manifest.Scopes[0].Patterns = append(manifest.Scopes[0].Patterns, filepath.Join(tempDir, "*.env"))
+
+	// When: resolving the file without an explicit scope
+	_, err := NewPlanner("sops").ResolveScope(manifest, file, "")
+
+	// Then: the ambiguity is reported
+	requireError(t, err, "file matching multiple scopes should be refused")
+	if !containsString(err.Error(), "multiple scopes") {
+		t.Errorf("expected ambiguity error, got: %v", err)
+	}
+}
+
+func TestPlanner_ComputePlan_RecipientDiff(t *testing.T) {
+	t.Parallel()
+
+	// Given: dev.env encrypted for alice, carol and a key missing from the manifest
+	tempDir := t.TempDir()
+	writeTestFile(t, tempDir, "dev.env", sopsDotenvContent(testRecipientA, testRecipientC, testRecipientD))
+	manifest := createScopedManifest(tempDir)
+
+	// When: computing the plan for a development scope of alice and bob
+	plan, err := NewPlanner("sops").ComputePlan(manifest)
+
+	// Then: bob gains access, carol loses it and the unknown key is flagged
+	requireNoError(t, err, "computing plan should succeed")
+	action := findActionForFile(t, plan, filepath.Join(tempDir, "dev.env"))
+	if action.Type != ActionReencrypt {
+		t.Errorf("expected re-encrypt action, got %s", action.Type)
+	}
+	verifyRecipients(t, action.AddedRecipients, []string{"bob"})
+	verifyRecipients(t, action.RemovedRecipients, []string{"carol"})
+	verifyRecipients(t, action.UnknownRecipients, []string{testRecipientD})
+}
+
+func TestPlanner_ComputePlan_NewFileAddsAllMembers(t *testing.T) {
+	t.Parallel()
+
+	// Given: a plaintext file in the development scope
+	tempDir := t.TempDir()
+	writeTestFile(t, tempDir, "dev.env", "KEY=value\n")
+	manifest := createScopedManifest(tempDir)
+
+	// When: computing the plan
+	plan, err := NewPlanner("sops").ComputePlan(manifest)
+
+	// Then: every scope member is reported as gaining access
+	requireNoError(t, err, "computing plan should succeed")
+	action := findActionForFile(t, plan, filepath.Join(tempDir, "dev.env"))
+	if action.Type != ActionEncrypt {
+		t.Errorf("expected encrypt action, got %s", action.Type)
+	}
+	verifyRecipients(t, action.AddedRecipients, []string{"alice", "bob"})
+	verifyRecipients(t, action.RemovedRecipients, []string{})
+}
+
+func TestPlanner_ComputePlan_SkipsFilesWithUnreadableMetadata(t *testing.T) {
+	t.Parallel()
+
+	// Given: an encrypted production file whose sops section cannot be parsed
+	tempDir := t.TempDir()
+	file := writeTestFile(t, tempDir, "prod.yaml", `password: ENC[AES256_GCM,data:abc=,iv:def=,tag:ghi=,type:str]
+sops:
+    age:
+        - recipient: `+testRecipientA+`
+    lastmodified: yesterday
+    mac: ENC[AES256_GCM,data:mac=,iv:def=,tag:ghi=,type:str]
+    version: 3.8.1
+`)
+	manifest := createScopedManifest(tempDir)
+	manifest.Scopes[1].Patterns = append(manifest.Scopes[1].Patterns, file)
+
+	// When: computing the plan
+	plan, err := NewPlanner("sops").ComputePlan(manifest)
+
+	// Then: the file is skipped with the parse error rather than encrypted again
+	requireNoError(t, err, "computing plan should succeed")
+	action := findActionForFile(t, plan, file)
+	if action.Type != ActionSkip || !containsString(action.Description, "lastmodified") {
+		t.Errorf("expected a skip action naming the parse error, got %s: %s", action.Type, action.Description)
+	}
+}
+
+func TestPlanner_ComputePlan_UpToDate(t *testing.T) {
+	t.Parallel()
+
+	// Given: dev.env already encrypted for exactly alice and bob
+	tempDir := t.TempDir()
+	writeTestFile(t, tempDir, "dev.env", sopsDotenvContent(testRecipientB, testRecipientA))
+	manifest := createScopedManifest(tempDir)
+	file := filepath.Join(tempDir, "dev.env")
+
+	// When: computing the plan
+	plan, err := NewPlanner("sops").ComputePlan(manifest)
+
+	// Then: the file is left untouched
+	requireNoError(t, err, "computing plan should succeed")
+	if action := findActionForFile(t, plan, file); action.Type != ActionNoop {
+		t.Errorf("expected noop action, got %s", action.Type)
+	}
+	if plan.HasChanges() {
+		t.Error("plan with only up-to-date files should have no changes")
+	}
+
+	// When: computing the plan with data key rotation requested
+	plan, err = NewPlanner("sops").WithRotateDataKeys(true).ComputePlan(manifest)
+
+	// Then: the file is re-encrypted anyway
+	requireNoError(t, err, "computing plan should succeed")
+	if action := findActionForFile(t, plan, file); action.Type != ActionReencrypt {
+		t.Errorf("expected re-encrypt action, got %s", action.Type)
+	}
+	if !plan.HasChanges() {
+		t.Error("plan rotating data keys should have changes")
+	}
+}
+
+func TestFormatRecipientChanges(t *testing.T) {
+	t.Parallel()
+
+	action := &Action{
+		AddedRecipients:   []string{"alice"},
+		RemovedRecipients: []string{"bob"},
+		UnknownRecipients: []string{testRecipientD},
+	}
+
+	got := formatRecipientChanges(action, true)
+	want := "+alice -bob -age1jps9575ntra0... (unknown)"
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+// Planner test helper functions
+
+func sopsDotenvContent(recipients ...string) string {
+	content := "KEY=ENC[AES256_GCM,data:abc=,iv:def=,tag:ghi=,type:str]\n"
+	for i, recipient := range recipients {
+		content += fmt.Sprintf("sops_age__list_%d__map_recipient=%s\n", i, recipient)
+	}
+	return content + "sops_lastmodified=2024-05-01T10:00:00Z\nsops_mac=ENC[AES256_GCM,data:mac=,iv:def=,tag:ghi=,type:str]\nsops_version=3.8.1\n"
+}
+
+func findActionForFile(t *testing.T, plan *Plan, file string) *Action {
+	t.Helper()
+
+	for i := range plan.Actions {
+		if plan.Actions[i].File == file {
+			return &plan.Actions[i]
+		}
+	}
+	t.Fatalf("no action planned for %s", file)
+	return nil
+}
+
+func createScopedManifest(dir string) *Manifest {
+	return &Manifest{
+		Members: []Member{
+			{ID: "alice", AgeKey: testRecipientA},
+			{ID: "bob", AgeKey: testRecipientB},
+			{ID: "carol", AgeKey: testRecipientC},
+		},
+		Scopes: []Scope{
+			{
+				Name:     "development",
+				Patterns: []string{filepath.Join(dir, "dev.env")},
+				Members:  []string{"alice", "bob"},
+			},
+			{
+				Name:     "production",
+				Patterns: []string{filepath.Join(dir, "prod.env")},
+				Members:  []string{"alice"},
+			},
+		},
+	}
+}
+
+func writeTestFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatalf("failed to create directory for %s: %v", path, err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+	return path
+}
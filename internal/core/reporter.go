@@ -0,0 +1,179 @@
+package core
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Level controls how much a Reporter prints
+type Level int
+
+// Reporter levels, from least to most output
+const (
+	LevelQuiet   Level = iota // Only primary output and warnings
+	LevelNormal               // Progress and status messages
+	LevelVerbose              // Extra detail such as backups and key file names
+	LevelDebug                // External commands being run
+)
+
+// Reporter is the single sink for everything core operations tell the user.
+// Primary output (decrypted files, JSON documents) goes to Out regardless of level.
+type Reporter interface {
+	Level() Level
+	Infof(format string, args ...any)
+	Verbosef(format string, args ...any)
+	Debugf(format string, args ...any)
+	Warnf(format string, args ...any)
+	Out() io.Writer
+	Colorize(text, color string) string
+	ColorEnabled() bool
+	StartProgress(total int) Progress
+}
+
+// Progress reports advancement through a fixed number of steps
+type Progress interface {
+	Step(format string, args ...any)
+}
+
+// ReporterOptions configures a ConsoleReporter
+type ReporterOptions struct {
+	Out     io.Writer // Defaults to os.Stdout
+	Err     io.Writer // Warnings; defaults to os.Stderr
+	Level   Level
+	NoColor bool // Disable colour and emoji even on a terminal
+}
+
+// ConsoleReporter writes human-readable messages. Colour and emoji are only
+// used when Out is a terminal, NoColor is unset and NO_COLOR is not set.
+type ConsoleReporter struct {
+	out    io.Writer
+	err    io.Writer
+	level  Level
+	styled bool
+}
+
+// NewConsoleReporter creates a reporter from opts
+func NewConsoleReporter(opts ReporterOptions) *ConsoleReporter {
+	if opts.Out == nil {
+		opts.Out = os.Stdout
+	}
+	if opts.Err == nil {
+		opts.Err = os.Stderr
+	}
+	return &ConsoleReporter{
+		out:    opts.Out,
+		err:    opts.Err,
+		level:  opts.Level,
+		styled: !opts.NoColor && os.Getenv("NO_COLOR") == "" && isTerminal(opts.Out),
+	}
+}
+
+// NewWriterReporter creates a plain reporter at normal level writing to w
+func NewWriterReporter(w io.Writer) *ConsoleReporter {
+	return NewConsoleReporter(ReporterOptions{Out: w, Err: w, Level: LevelNormal, NoColor: true})
+}
+
+// Level returns the configured verbosity
+func (r *ConsoleReporter) Level() Level {
+	return r.level
+}
+
+// Infof prints a status message at normal level
+func (r *ConsoleReporter) Infof(format string, args ...any) {
+	r.printAt(LevelNormal, r.out, format, args...)
+}
+
+// Verbosef prints a detail message at verbose level
+func (r *ConsoleReporter) Verbosef(format string, args ...any) {
+	r.printAt(LevelVerbose, r.out, format, args...)
+}
+
+// Debugf prints a diagnostic message at debug level
+func (r *ConsoleReporter) Debugf(format string, args ...any) {
+	r.printAt(LevelDebug, r.out, format, args...)
+}
+
+// Warnf prints a warning at every level, including quiet
+func (r *ConsoleReporter) Warnf(format string, args ...any) {
+	r.printAt(LevelQuiet, r.err, format, args...)
+}
+
+// Out returns the writer for primary output
+func (r *ConsoleReporter) Out() io.Writer {
+	return r.out
+}
+
+// Colorize wraps text in the given ANSI colour when colour is enabled
+func (r *ConsoleReporter) Colorize(text, color string) string {
+	if !r.styled {
+		return text
+	}
+	return color + text + ansiReset
+}
+
+// ColorEnabled reports whether ANSI colour and emoji are used
+func (r *ConsoleReporter) ColorEnabled() bool {
+	return r.styled
+}
+
+// StartProgress returns a progress display counting up to total steps
+func (r *ConsoleReporter) StartProgress(total int) Progress {
+	return &consoleProgress{reporter: r, total: total}
+}
+
+func (r *ConsoleReporter) printAt(level Level, w io.Writer, format string, args ...any) {
+	if r.level < level {
+		return
+	}
+	msg := fmt.Sprintf(format, args...)
+	if !r.styled {
+		msg = stripLeadingEmoji(msg)
+	}
+	_, _ = fmt.Fprintln(w, msg)
+}
+
+type consoleProgress struct {
+	reporter *ConsoleReporter
+	current  int
+	total    int
+}
+
+func (p *consoleProgress) Step(format string, args ...any) {
+	p.current++
+	width := len(fmt.Sprint(p.total))
+	mark := ""
+	if p.reporter.styled {
+		mark = "✓ "
+	}
+	p.reporter.Infof("[%*d/%d] %s%s", width, p.current, p.total, mark, fmt.Sprintf(format, args...))
+}
+
+// isTerminal reports whether w is a character device such as a TTY
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// stripLeadingEmoji removes the emoji or symbol that prefixes each line of msg
+func stripLeadingEmoji(msg string) string {
+	lines := strings.Split(msg, "\n")
+	for i, line := range lines {
+		indent := line[:len(line)-len(strings.TrimLeft(line, " "))]
+		rest := line[len(indent):]
+		r, _ := utf8.DecodeRuneInString(rest)
+		if r < utf8.RuneSelf || unicode.IsLetter(r) {
+			continue
+		}
+		rest = strings.TrimLeftFunc(rest, func(r rune) bool { return r >= utf8.RuneSelf && !unicode.IsLetter(r) })
+		lines[i] = indent + strings.TrimLeft(rest, " ")
+	}
+	return strings.Join(lines, "\n")
+}
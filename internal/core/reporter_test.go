@@ -0,0 +1,82 @@
+package core
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestConsoleReporter_Levels(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		want  string
+		level Level
+	}{
+		{name: "quiet shows only warnings", level: LevelQuiet, want: "warn\n"},
+		{name: "normal adds status messages", level: LevelNormal, want: "info\nwarn\n"},
+		{name: "verbose adds detail", level: LevelVerbose, want: "info\nverbose\nwarn\n"},
+		{name: "debug shows everything", level: LevelDebug, want: "info\nverbose\ndebug\nwarn\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given: a reporter at the given level
+			var output bytes.Buffer
+			reporter := NewConsoleReporter(ReporterOptions{Out: &output, Err: &output, Level: tt.level})
+
+			// When: reporting at every level
+			reporter.Infof("info")
+			reporter.Verbosef("verbose")
+			reporter.Debugf("debug")
+			reporter.Warnf("warn")
+
+			// Then: only messages at or below the level are shown
+			if got := output.String(); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestConsoleReporter_PlainOutput(t *testing.T) {
+	t.Parallel()
+
+	// Given: a reporter writing to a non-terminal
+	var output bytes.Buffer
+	reporter := NewWriterReporter(&output)
+
+	// When: reporting emoji-prefixed and coloured messages
+	reporter.Infof("🧑‍💻  Added %s as team member", "alice")
+	reporter.Infof("\n⚠️  %s: not encrypted", "dev.env")
+	reporter.Infof("  Access: %s", reporter.Colorize("+bob", ansiGreen))
+
+	// Then: emoji and colour codes are left out
+	want := "Added alice as team member\n\ndev.env: not encrypted\n  Access: +bob\n"
+	if got := output.String(); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	if reporter.ColorEnabled() {
+		t.Error("colour should be disabled for non-terminal output")
+	}
+}
+
+func TestConsoleReporter_Progress(t *testing.T) {
+	t.Parallel()
+
+	// Given: a progress display over ten steps
+	var output bytes.Buffer
+	progress := NewWriterReporter(&output).StartProgress(10)
+
+	// When: advancing twice
+	progress.Step("re-encrypt %s", "a.env")
+	progress.Step("re-encrypt %s", "b.env")
+
+	// Then: each step is counted against the total
+	want := "[ 1/10] re-encrypt a.env\n[ 2/10] re-encrypt b.env\n"
+	if got := output.String(); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,93 @@
+package core
+
+import "fmt"
+
+// AddScope creates a scope for the given file patterns and members
+func (s *SopsManager) AddScope(name string, patterns, members []string) error {
+	if _, err := NewScopeName(name); err != nil {
+		return NewManifestError("validate", "scope "+name, err)
+	}
+
+	return s.changeScope("add", name, "Added scope "+name, func(editor *ManifestEditor) error {
+		return editor.AddScope(Scope{Name: name, Patterns: patterns, Members: members})
+	})
+}
+
+// RemoveScope deletes a scope. Files it matched are no longer managed.
+func (s *SopsManager) RemoveScope(name string) error {
+	return s.changeScope("remove", name, "Removed scope "+name, func(editor *ManifestEditor) error {
+		return editor.RemoveScope(name)
+	})
+}
+
+// RenameScope renames a scope, keeping its members and patterns
+func (s *SopsManager) RenameScope(oldName, newName string) error {
+	if _, err := NewScopeName(newName); err != nil {
+		return NewManifestError("validate", "scope "+newName, err)
+	}
+
+	return s.changeScope("rename", newName, fmt.Sprintf("Renamed scope %s to %s", oldName, newName), func(editor *ManifestEditor) error {
+		return editor.RenameScope(oldName, newName)
+	})
+}
+
+// AddScopeMember grants an existing member access to a scope
+func (s *SopsManager) AddScopeMember(scope, memberID string) error {
+	return s.changeScope("add-member", scope, fmt.Sprintf("Added %s to scope %s", memberID, scope), func(editor *ManifestEditor) error {
+		return editor.AddScopeMember(scope, memberID)
+	})
+}
+
+// RemoveScopeMember revokes a member's access to a scope
+func (s *SopsManager) RemoveScopeMember(scope, memberID string) error {
+	return s.changeScope("remove-member", scope, fmt.Sprintf("Removed %s from scope %s", memberID, scope), func(editor *ManifestEditor) error {
+		return editor.RemoveScopeMember(scope, memberID)
+	})
+}
+
+// AddScopePattern adds a file pattern to a scope
+func (s *SopsManager) AddScopePattern(scope, pattern string) error {
+	return s.changeScope("add-pattern", scope, fmt.Sprintf("Added pattern %s to scope %s", pattern, scope), func(editor *ManifestEditor) error {
+		return editor.AddScopePattern(scope, pattern)
+	})
+}
+
+// RemoveScopePattern removes a file pattern from a scope
+func (s *SopsManager) RemoveScopePattern(scope, pattern string) error {
+	return s.changeScope("remove-pattern", scope, fmt.Sprintf("Removed pattern %s from scope %s", pattern, scope), func(editor *ManifestEditor) error {
+		return editor.RemoveScopePattern(scope, pattern)
+	})
+}
+
+// changeScope applies a validated edit to the manifest and reports how the
+// plan looks afterwards, so the effect of the change is visible immediately
+func (s *SopsManager) changeScope(change, scope, message string, edit func(*ManifestEditor) error) error {
+	return s.editScopes("scope "+change, change, scope, message, edit)
+}
+
+// editScopes is changeScope for any command, reporting its JSON result under command
+func (s *SopsManager) editScopes(command, change, scope, message string, edit func(*ManifestEditor) error) error {
+	if _, err := LoadManifest(s.configPath); err != nil {
+		return fmt.Errorf(FailedToLoadManifestMsg, err)
+	}
+
+	if err := s.editManifest(edit); err != nil {
+		return fmt.Errorf("failed to update scope %s: %w", scope, err)
+	}
+
+	plan, err := s.computePlan(false)
+	if err != nil {
+		return fmt.Errorf("scope updated, but computing the plan failed: %w", err)
+	}
+
+	if s.jsonOutput {
+		return WriteJSONResult(s.reporter.Out(), command, ScopeResult{Scope: scope, Change: change, Plan: plan.Result("")})
+	}
+
+	s.reporter.Infof("%s", message)
+	s.reporter.Infof("Plan: %s", plan.Summary())
+	if plan.HasChanges() {
+		s.reporter.Infof("Run 'sistry plan' to see changes, then 'sistry apply' to re-encrypt files")
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package core
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func setupScopeTest(t *testing.T) (*SopsManager, *bytes.Buffer, string) {
+	t.Helper()
+
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "sopsistry.yaml")
+	requireNoError(t, createScopedManifest(tempDir).Save(configPath), "saving manifest should succeed")
+
+	var output bytes.Buffer
+	service := &SopsManager{sopsPath: "sops", configPath: configPath, secretsDir: ".secrets", reporter: NewWriterReporter(&output)}
+	return service, &output, tempDir
+}
+
+func TestSopsManager_ScopeChanges(t *testing.T) {
+	t.Parallel()
+
+	// Given: a development scope for alice and bob, and a plaintext file it does not match yet
+	service, output, tempDir := setupScopeTest(t)
+	writeTestFile(t, tempDir, "staging.env", "KEY=value\n")
+
+	// When: renaming it, adding carol and a pattern, and creating a new scope
+	requireNoError(t, service.RenameScope("development", "dev"), "rename should succeed")
+	requireNoError(t, service.AddScopeMember("dev", "carol"), "adding member should succeed")
+	requireNoError(t, service.RemoveScopeMember("dev", "bob"), "removing member should succeed")
+	requireNoError(t, service.AddScopePattern("dev", filepath.Join(tempDir, "staging.env")), "adding pattern should succeed")
+	requireNoError(t, service.AddScope("qa", []string{filepath.Join(tempDir, "qa.env")}, nil), "adding scope should succeed")
+
+	// Then: the manifest reflects every change
+	manifest := loadManifestOrFail(t, service.configPath)
+	dev := manifest.FindScope("dev")
+	if dev == nil || manifest.FindScope("development") != nil {
+		t.Fatalf("expected development to be renamed to dev, got %+v", manifest.Scopes)
+	}
+	if !slices.Equal(dev.Members, []string{"alice", "carol"}) {
+		t.Errorf("expected members alice and carol, got %v", dev.Members)
+	}
+	if len(dev.Patterns) != 2 {
+		t.Errorf("expected two patterns, got %v", dev.Patterns)
+	}
+	if manifest.FindScope("qa") == nil {
+		t.Error("expected qa scope to be added")
+	}
+
+	// Then: the resulting plan is summarized after each change
+	if !containsString(output.String(), "Plan: 1 to encrypt, 0 to re-encrypt, 0 up to date") {
+		t.Errorf("expected plan summary for staging.env, got: %s", output.String())
+	}
+
+	// When: removing the new scope
+	requireNoError(t, service.RemoveScope("qa"), "removing scope should succeed")
+
+	// Then: it is gone
+	if loadManifestOrFail(t, service.configPath).FindScope("qa") != nil {
+		t.Error("expected qa scope to be removed")
+	}
+}
+
+func TestSopsManager_ScopeChangesAreValidated(t *testing.T) {
+	t.Parallel()
+
+	// Given: a manifest whose production scope has a single pattern
+	service, _, _ := setupScopeTest(t)
+	before, err := os.ReadFile(service.configPath)
+	requireNoError(t, err, "reading manifest should succeed")
+
+	tests := []struct {
+		change func() error
+		name   string
+	}{
+		{name: "unknown member", change: func() error { return service.AddScopeMember("production", "mallory") }},
+		{name: "unknown scope", change: func() error { return service.AddScopePattern("staging", "*.env") }},
+		{name: "duplicate scope", change: func() error { return service.RenameScope("production", "development") }},
+		{name: "last pattern removed", change: func() error {
+			scope := loadManifestOrFail(t, service.configPath).FindScope("production")
+			return service.RemoveScopePattern("production", scope.Patterns[0])
+		}},
+		{name: "invalid scope name", change: func() error { return service.AddScope("my scope", []string{"*.env"}, nil) }},
+	}
+
+	for _, tt := range tests {
+		// When: making a change that would leave the manifest invalid
+		err := tt.change()
+
+		// Then: it is refused and the manifest is untouched
+		requireError(t, err, tt.name+" should be refused")
+		after, readErr := os.ReadFile(service.configPath)
+		requireNoError(t, readErr, "reading manifest should succeed")
+		if !bytes.Equal(before, after) {
+			t.Errorf("%s: manifest changed despite the error", tt.name)
+		}
+	}
+}
+
+func TestSopsManager_AddMemberScopes(t *testing.T) {
+	t.Parallel()
+
+	// Given: a manifest whose new members join production by default
+	service, _, _ := setupScopeTest(t)
+	manifest := loadManifestOrFail(t, service.configPath)
+	manifest.DefaultScopes = []string{"production"}
+	requireNoError(t, manifest.Save(service.configPath), "saving manifest should succeed")
+
+	// When: adding a member without --scope, and another with explicit scopes
+	requireNoError(t, service.AddMember("dave", testRecipientD), "adding member should succeed")
+	requireNoError(t, service.AddMember("erin", testRecipientD, "development"), "adding member should succeed")
+
+	// Then: the first joins the default scopes, the second only the given ones
+	manifest = loadManifestOrFail(t, service.configPath)
+	production, development := manifest.FindScope("production"), manifest.FindScope("development")
+	if !slices.Contains(production.Members, "dave") || slices.Contains(development.Members, "dave") {
+		t.Errorf("expected dave only in production, got production %v, development %v", production.Members, development.Members)
+	}
+	if slices.Contains(production.Members, "erin") || !slices.Contains(development.Members, "erin") {
+		t.Errorf("expected erin only in development, got production %v, development %v", production.Members, development.Members)
+	}
+
+	// When: renaming the default scope
+	requireNoError(t, service.RenameScope("production", "prod"), "rename should succeed")
+
+	// Then: default_scopes follows the rename
+	if got := loadManifestOrFail(t, service.configPath).DefaultScopes; !slices.Equal(got, []string{"prod"}) {
+		t.Errorf("expected default scopes [prod], got %v", got)
+	}
+}
+
+func TestSopsManager_AddMemberWithoutScope(t *testing.T) {
+	t.Parallel()
+
+	// Given: a manifest with no default_scopes and no scope named "default"
+	service, _, _ := setupScopeTest(t)
+
+	// When: adding a member without --scope
+	err := service.AddMember("dave", testRecipientD)
+
+	// Then: it is refused instead of adding dave to no scope
+	requireError(t, err, "member without a resolvable scope should be refused")
+	if _, found := loadManifestOrFail(t, service.configPath).GetMemberAgeKey("dave"); found {
+		t.Error("dave should not have been added")
+	}
+
+	// When: naming a scope that does not exist
+	err = service.AddMember("dave", testRecipientD, "staging")
+
+	// Then: it is refused as well
+	requireError(t, err, "unknown scope should be refused")
+}
+
+func TestSopsManager_RemoveInheritedScopeIsRefused(t *testing.T) {
+	t.Parallel()
+
+	// Given: staging inherits production's members
+	service, _, tempDir := setupScopeTest(t)
+	writeTestFile(t, tempDir, "sopsistry.yaml", `members:
+  - id: alice
+    age_key: `+testRecipientA+`
+scopes:
+  - name: production
+    patterns: ["prod.env"]
+    members: [alice]
+  - name: staging
+    patterns: ["staging.env"]
+    members: []
+    inherits_members_from: [production]
+`)
+
+	// When: removing production
+	err := service.RemoveScope("production")
+
+	// Then: it is refused, naming the inheriting scope, and nothing changes
+	if err == nil || !containsString(err.Error(), "scope production is inherited by: staging") {
+		t.Errorf("expected removal to be refused, got %v", err)
+	}
+	if loadManifestOrFail(t, service.configPath).FindScope("production") == nil {
+		t.Error("expected production to be kept")
+	}
+}
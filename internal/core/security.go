@@ -10,10 +10,12 @@ import (
 const (
 	DefaultSOPSBinary = "sops"
 	AgeKeygenBinary   = "age-keygen"
+	SSHKeygenBinary   = "ssh-keygen"
 
 	PrivateKeyFileMode = 0o600 // Read/write for owner only
 	BackupDirMode      = 0o700 // Read/write/execute for owner only
 	GitignoreFileMode  = 0o644 // Read/write for owner, read for group/others
+	RequestsDirMode    = 0o755 // Committed directory, readable by everyone
 
 	HoursPerDay          = 24
 	DefaultSliceCapacity = 32
@@ -21,7 +23,8 @@ const (
 	DateFormat              = "2006-01-02"
 	FailedToLoadManifestMsg = "failed to load manifest: %w"
 
-	DefaultMaxKeyAgeDays = 180
+	DefaultMaxKeyAgeDays        = 180
+	DefaultMaxMachineKeyAgeDays = 90
 )
 
 // ValidSOPSPath represents a validated and safe SOPS executable path
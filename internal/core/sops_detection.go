@@ -47,13 +47,13 @@ func (d *SOPSDetector) DetectSOPSConfig() (*SOPSConfigInfo, error) {
 
 // SOPSConfigInfo contains information about existing SOPS configuration
 type SOPSConfigInfo struct {
-	ConfigPath       string
-	Content          string
-	Exists           bool
-	HasCreationRules bool
-	HasAgeKeys       bool
-	HasKMSKeys       bool
-	HasPGPKeys       bool
+	ConfigPath       string `json:"config_path"`
+	Content          string `json:"-"`
+	Exists           bool   `json:"exists"`
+	HasCreationRules bool   `json:"has_creation_rules"`
+	HasAgeKeys       bool   `json:"has_age_keys"`
+	HasKMSKeys       bool   `json:"has_kms_keys"`
+	HasPGPKeys       bool   `json:"has_pgp_keys"`
 }
 
 // ShouldWarn determines if we should warn about conflicts
@@ -10,6 +10,7 @@ import (
 
 // SOPSHelper provides utilities for working with SOPS commands
 type SOPSHelper struct {
+	reporter   Reporter
 	sopsPath   string
 	secretsDir string
 }
@@ -21,11 +22,18 @@ func NewSOPSHelper(sopsPath, secretsDir string) *SOPSHelper {
 	}
 	cleanPath := filepath.Clean(sopsPath)
 	return &SOPSHelper{
+		reporter:   NewConsoleReporter(ReporterOptions{Level: LevelNormal}),
 		sopsPath:   cleanPath,
 		secretsDir: secretsDir,
 	}
 }
 
+// WithReporter sets where the command and its examples are shown
+func (h *SOPSHelper) WithReporter(reporter Reporter) *SOPSHelper {
+	h.reporter = reporter
+	return h
+}
+
 // ShowCommand displays a SOPS command with proper environment variables
 func (h *SOPSHelper) ShowCommand(args, ageKeys []string) error {
 	return h.processCommand(args, ageKeys, false)
@@ -55,30 +63,32 @@ func (h *SOPSHelper) processCommand(args, ageKeys []string, execute bool) error
 		cmd.Env = append(os.Environ(), envVars...)
 
 		cmd.Stdin = os.Stdin
-		cmd.Stdout = os.Stdout
+		cmd.Stdout = h.reporter.Out()
 		cmd.Stderr = os.Stderr
 
-		fmt.Printf("🔧 Executing: %s %s\n", h.sopsPath, strings.Join(args, " "))
+		h.reporter.Infof("🔧 Executing: %s %s", h.sopsPath, strings.Join(args, " "))
 		return cmd.Run()
 	}
 
-	fmt.Printf("🔧 SOPS command with team environment:\n\n")
+	// The command itself is primary output, so it is shown even in quiet mode
+	out := h.reporter.Out()
+	h.reporter.Infof("🔧 SOPS command with team environment:\n")
 
 	for _, env := range envVars {
-		fmt.Printf("export %s\n", env)
+		_, _ = fmt.Fprintf(out, "export %s\n", env)
 	}
 
-	fmt.Printf("%s %s\n", h.sopsPath, strings.Join(args, " "))
+	_, _ = fmt.Fprintf(out, "%s %s\n", h.sopsPath, strings.Join(args, " "))
 
-	fmt.Printf("\n💡 Common partial encryption examples:\n")
-	fmt.Printf("# Encrypt only password/key fields in .env:\n")
-	fmt.Printf("%s -e --encrypted-regex '^(.*password.*|.*key.*)$' .env\n\n", h.sopsPath)
+	h.reporter.Infof("\n💡 Common partial encryption examples:")
+	h.reporter.Infof("# Encrypt only password/key fields in .env:")
+	h.reporter.Infof("%s -e --encrypted-regex '^(.*password.*|.*key.*)$' .env\n", h.sopsPath)
 
-	fmt.Printf("# Encrypt specific fields in YAML:\n")
-	fmt.Printf("%s -e --encrypted-regex '^(password|secret|key)$' config.yaml\n\n", h.sopsPath)
+	h.reporter.Infof("# Encrypt specific fields in YAML:")
+	h.reporter.Infof("%s -e --encrypted-regex '^(password|secret|key)$' config.yaml\n", h.sopsPath)
 
-	fmt.Printf("# Decrypt file:\n")
-	fmt.Printf("%s -d encrypted-file.yaml\n", h.sopsPath)
+	h.reporter.Infof("# Decrypt file:")
+	h.reporter.Infof("%s -d encrypted-file.yaml", h.sopsPath)
 
 	return nil
 }
@@ -0,0 +1,421 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SOPSFormat is the file format SOPS uses to store a file, derived from its extension
+type SOPSFormat string
+
+// File formats understood by SOPS
+const (
+	FormatYAML   SOPSFormat = "yaml"
+	FormatJSON   SOPSFormat = "json"
+	FormatDotenv SOPSFormat = "dotenv"
+	FormatINI    SOPSFormat = "ini"
+	FormatBinary SOPSFormat = "binary"
+)
+
+// EncryptionState describes how much of a file SOPS has encrypted
+type EncryptionState string
+
+// Encryption states of a file
+const (
+	StatePlaintext          EncryptionState = "plaintext"
+	StatePartiallyEncrypted EncryptionState = "partially-encrypted"
+	StateFullyEncrypted     EncryptionState = "fully-encrypted"
+)
+
+const (
+	sopsMetadataKey          = "sops"
+	sopsDotenvPrefix         = "sops_"
+	sopsEncryptedValuePrefix = "ENC["
+)
+
+// SOPSMetadata is the parsed "sops" section of a file
+type SOPSMetadata struct { //nolint:govet // Field alignment optimization not critical for this struct
+	LastModified      time.Time       `json:"lastmodified"`
+	Format            SOPSFormat      `json:"format"`
+	State             EncryptionState `json:"state"`
+	AgeRecipients     []string        `json:"age_recipients"`
+	MAC               string          `json:"mac,omitempty"`
+	Version           string          `json:"version,omitempty"`
+	EncryptedRegex    string          `json:"encrypted_regex,omitempty"`
+	UnencryptedSuffix string          `json:"unencrypted_suffix,omitempty"`
+}
+
+// IsEncrypted reports whether the file carries SOPS metadata
+func (m *SOPSMetadata) IsEncrypted() bool {
+	return m.State != StatePlaintext
+}
+
+// sopsAgeEntry is a single age recipient in the sops section
+type sopsAgeEntry struct {
+	Recipient string `yaml:"recipient" json:"recipient"`
+}
+
+// sopsMetadataDoc mirrors the structured "sops" section written to YAML, JSON and binary files
+type sopsMetadataDoc struct { //nolint:govet // Field alignment optimization not critical for this struct
+	Age               []sopsAgeEntry `yaml:"age" json:"age"`
+	KMS               []any          `yaml:"kms" json:"kms"`
+	GCPKMS            []any          `yaml:"gcp_kms" json:"gcp_kms"`
+	AzureKV           []any          `yaml:"azure_kv" json:"azure_kv"`
+	HCVault           []any          `yaml:"hc_vault" json:"hc_vault"`
+	PGP               []any          `yaml:"pgp" json:"pgp"`
+	KeyGroups         []any          `yaml:"key_groups" json:"key_groups"`
+	LastModified      string         `yaml:"lastmodified" json:"lastmodified"`
+	MAC               string         `yaml:"mac" json:"mac"`
+	Version           string         `yaml:"version" json:"version"`
+	EncryptedRegex    string         `yaml:"encrypted_regex" json:"encrypted_regex"`
+	UnencryptedSuffix string         `yaml:"unencrypted_suffix" json:"unencrypted_suffix"`
+
+	flatMasterKeys int // Master keys other than age found in dotenv and INI files
+}
+
+// isPresent reports whether the section holds a MAC and at least one master
+// key, which SOPS always writes, so that a plaintext file with a "sops" key
+// or a stray sops_version= line is not mistaken for an encrypted one
+func (d *sopsMetadataDoc) isPresent() bool {
+	masterKeys := len(d.Age) + len(d.KMS) + len(d.GCPKMS) + len(d.AzureKV) + len(d.HCVault) + len(d.PGP) + len(d.KeyGroups) + d.flatMasterKeys
+	return d.MAC != "" && masterKeys > 0
+}
+
+// leafCounts tallies encrypted and plaintext values outside the sops section
+type leafCounts struct {
+	encrypted int
+	plain     int
+}
+
+func (c *leafCounts) add(value string) {
+	if isEncryptedValue(value) {
+		c.encrypted++
+	} else {
+		c.plain++
+	}
+}
+
+func isEncryptedValue(value string) bool {
+	return strings.HasPrefix(value, sopsEncryptedValuePrefix) && strings.HasSuffix(value, "]")
+}
+
+// DetectSOPSFormat returns the format SOPS would use for path, following SOPS' own extension rules
+func DetectSOPSFormat(path string) SOPSFormat {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FormatYAML
+	case ".json":
+		return FormatJSON
+	case ".env":
+		return FormatDotenv
+	case ".ini":
+		return FormatINI
+	default:
+		return FormatBinary
+	}
+}
+
+// ReadSOPSMetadata parses the SOPS metadata of a file. Files without metadata
+// are reported with StatePlaintext rather than as an error.
+func ReadSOPSMetadata(path string) (*SOPSMetadata, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // Reading project files for analysis is expected
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	metadata, err := ParseSOPSMetadata(data, DetectSOPSFormat(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse SOPS metadata of %s: %w", path, err)
+	}
+	return metadata, nil
+}
+
+// ParseSOPSMetadata parses SOPS metadata from file content in the given format
+func ParseSOPSMetadata(data []byte, format SOPSFormat) (*SOPSMetadata, error) {
+	switch format {
+	case FormatYAML:
+		return parseYAMLMetadata(data)
+	case FormatJSON:
+		return parseJSONMetadata(data, format)
+	case FormatDotenv:
+		return parseDotenvMetadata(data)
+	case FormatINI:
+		return parseINIMetadata(data)
+	case FormatBinary:
+		return parseBinaryMetadata(data)
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+func parseYAMLMetadata(data []byte) (*SOPSMetadata, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	var doc *sopsMetadataDoc
+	counts := &leafCounts{}
+
+	for {
+		var node yaml.Node
+		if err := decoder.Decode(&node); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+		found, err := scanYAMLDocument(&node, counts)
+		if err != nil {
+			return nil, err
+		}
+		if found != nil {
+			doc = found
+		}
+	}
+
+	return buildMetadata(FormatYAML, doc, counts)
+}
+
+// scanYAMLDocument counts leaf values of a document and extracts its sops section, if any
+func scanYAMLDocument(node *yaml.Node, counts *leafCounts) (*sopsMetadataDoc, error) {
+	if len(node.Content) == 0 || node.Content[0].Kind != yaml.MappingNode {
+		countYAMLLeaves(node, counts)
+		return nil, nil
+	}
+
+	var doc *sopsMetadataDoc
+	root := node.Content[0]
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value != sopsMetadataKey || root.Content[i+1].Kind != yaml.MappingNode {
+			countYAMLLeaves(root.Content[i+1], counts)
+			continue
+		}
+		doc = &sopsMetadataDoc{}
+		if err := root.Content[i+1].Decode(doc); err != nil {
+			return nil, fmt.Errorf("invalid sops section: %w", err)
+		}
+	}
+	return doc, nil
+}
+
+func countYAMLLeaves(node *yaml.Node, counts *leafCounts) {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		counts.add(node.Value)
+	case yaml.MappingNode:
+		for i := 1; i < len(node.Content); i += 2 {
+			countYAMLLeaves(node.Content[i], counts)
+		}
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, child := range node.Content {
+			countYAMLLeaves(child, counts)
+		}
+	case yaml.AliasNode:
+		// Aliases repeat values already counted at their anchor
+	}
+}
+
+func parseJSONMetadata(data []byte, format SOPSFormat) (*SOPSMetadata, error) {
+	var root map[string]json.RawMessage
+	if err := json.Unmarshal(data, &root); err != nil {
+		// SOPS always writes an object, so other valid documents are plaintext
+		var typeErr *json.UnmarshalTypeError
+		if format == FormatBinary || errors.As(err, &typeErr) {
+			return buildMetadata(format, nil, &leafCounts{})
+		}
+		return nil, err
+	}
+
+	counts := &leafCounts{}
+	for key, raw := range root {
+		if key == sopsMetadataKey {
+			continue
+		}
+		var value any
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, err
+		}
+		countJSONLeaves(value, counts)
+	}
+
+	raw, ok := root[sopsMetadataKey]
+	if !ok {
+		return buildMetadata(format, nil, counts)
+	}
+
+	doc := &sopsMetadataDoc{}
+	if err := json.Unmarshal(raw, doc); err != nil {
+		return nil, fmt.Errorf("invalid sops section: %w", err)
+	}
+	return buildMetadata(format, doc, counts)
+}
+
+func countJSONLeaves(value any, counts *leafCounts) {
+	switch v := value.(type) {
+	case map[string]any:
+		for _, child := range v {
+			countJSONLeaves(child, counts)
+		}
+	case []any:
+		for _, child := range v {
+			countJSONLeaves(child, counts)
+		}
+	case string:
+		counts.add(v)
+	default:
+		counts.plain++
+	}
+}
+
+// parseBinaryMetadata handles files SOPS stores as a JSON envelope with "data" and "sops" keys
+func parseBinaryMetadata(data []byte) (*SOPSMetadata, error) {
+	if !json.Valid(data) {
+		return buildMetadata(FormatBinary, nil, &leafCounts{})
+	}
+	return parseJSONMetadata(data, FormatBinary)
+}
+
+func parseDotenvMetadata(data []byte) (*SOPSMetadata, error) {
+	sopsValues := make(map[string]string)
+	counts := &leafCounts{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		if after, ok := strings.CutPrefix(key, sopsDotenvPrefix); ok {
+			sopsValues[after] = value
+			continue
+		}
+		counts.add(value)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return buildMetadata(FormatDotenv, flatMetadataDoc(sopsValues), counts)
+}
+
+func parseINIMetadata(data []byte) (*SOPSMetadata, error) {
+	sopsValues := make(map[string]string)
+	counts := &leafCounts{}
+	section := ""
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, ";") || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if section == sopsMetadataKey {
+			sopsValues[key] = value
+			continue
+		}
+		counts.add(value)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return buildMetadata(FormatINI, flatMetadataDoc(sopsValues), counts)
+}
+
+var (
+	flatAgeRecipientKey = regexp.MustCompile(`^age__list_(\d+)__map_recipient$`)
+	// flatMasterKey matches the entries SOPS writes for every other master key
+	flatMasterKey = regexp.MustCompile(`^(pgp|kms|gcp_kms|azure_kv|hc_vault)__list_(\d+)__map_`)
+)
+
+// flatMetadataDoc rebuilds the sops section from the flattened keys used by dotenv and INI files
+func flatMetadataDoc(values map[string]string) *sopsMetadataDoc {
+	if len(values) == 0 {
+		return nil
+	}
+
+	doc := &sopsMetadataDoc{
+		LastModified:      values["lastmodified"],
+		MAC:               values["mac"],
+		Version:           values["version"],
+		EncryptedRegex:    values["encrypted_regex"],
+		UnencryptedSuffix: values["unencrypted_suffix"],
+	}
+
+	var indices []int
+	recipients := make(map[int]string)
+	masterKeys := NewSet[string]()
+	for key, value := range values {
+		if match := flatAgeRecipientKey.FindStringSubmatch(key); match != nil {
+			index, _ := strconv.Atoi(match[1]) //nolint:errcheck // Regex guarantees digits
+			indices = append(indices, index)
+			recipients[index] = value
+		} else if match := flatMasterKey.FindStringSubmatch(key); match != nil {
+			masterKeys.Add(match[1] + "/" + match[2])
+		}
+	}
+	doc.flatMasterKeys = masterKeys.Size()
+	slices.Sort(indices)
+	for _, index := range indices {
+		doc.Age = append(doc.Age, sopsAgeEntry{Recipient: recipients[index]})
+	}
+
+	return doc
+}
+
+func buildMetadata(format SOPSFormat, doc *sopsMetadataDoc, counts *leafCounts) (*SOPSMetadata, error) {
+	metadata := &SOPSMetadata{
+		Format:        format,
+		State:         StatePlaintext,
+		AgeRecipients: []string{},
+	}
+	if doc == nil || !doc.isPresent() {
+		return metadata, nil
+	}
+
+	metadata.State = StateFullyEncrypted
+	if counts.plain > 0 {
+		metadata.State = StatePartiallyEncrypted
+	}
+
+	metadata.MAC = doc.MAC
+	metadata.Version = doc.Version
+	metadata.EncryptedRegex = doc.EncryptedRegex
+	metadata.UnencryptedSuffix = doc.UnencryptedSuffix
+	for _, age := range doc.Age {
+		metadata.AgeRecipients = append(metadata.AgeRecipients, strings.TrimSpace(age.Recipient))
+	}
+
+	if doc.LastModified != "" {
+		lastModified, err := time.Parse(time.RFC3339, doc.LastModified)
+		if err != nil {
+			return nil, fmt.Errorf("invalid lastmodified %q: %w", doc.LastModified, err)
+		}
+		metadata.LastModified = lastModified
+	}
+
+	return metadata, nil
+}
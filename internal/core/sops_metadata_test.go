@@ -0,0 +1,282 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+const (
+	testRecipientA = "age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p"
+	testRecipientB = "age1lggyhqrw2nlhcxprm67z43rta597azn8gknawjehu9d9dl0jq3yqqvfafg"
+	testRecipientC = "age1mrq43ptqem2m5nxtcfjlz6plhrjrzw0zufzrgq6azq40rttwdlx7s29keu"
+	testRecipientD = "age1jps9575ntra0y4pc7j4rppjym65kekd8pktsyrm2axhjf48ef5ez30amly"
+)
+
+func TestParseSOPSMetadata_Formats(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct { //nolint:govet // Test struct field alignment not critical
+		name       string
+		format     SOPSFormat
+		content    string
+		wantState  EncryptionState
+		recipients []string
+	}{
+		{
+			name:   "fully encrypted yaml",
+			format: FormatYAML,
+			content: `password: ENC[AES256_GCM,data:abc=,iv:def=,tag:ghi=,type:str]
+nested:
+    token: ENC[AES256_GCM,data:xyz=,iv:def=,tag:ghi=,type:str]
+sops:
+    age:
+        - recipient: ` + testRecipientA + `
+          enc: |
+            -----BEGIN AGE ENCRYPTED FILE-----
+            -----END AGE ENCRYPTED FILE-----
+        - recipient: ` + testRecipientB + `
+          enc: |
+            -----BEGIN AGE ENCRYPTED FILE-----
+            -----END AGE ENCRYPTED FILE-----
+    lastmodified: "2024-05-01T10:00:00Z"
+    mac: ENC[AES256_GCM,data:mac=,iv:def=,tag:ghi=,type:str]
+    unencrypted_suffix: _unencrypted
+    version: 3.8.1
+`,
+			wantState:  StateFullyEncrypted,
+			recipients: []string{testRecipientA, testRecipientB},
+		},
+		{
+			name:   "partially encrypted yaml",
+			format: FormatYAML,
+			content: `password: ENC[AES256_GCM,data:abc=,iv:def=,tag:ghi=,type:str]
+hostname: db.internal
+sops:
+    age:
+        - recipient: ` + testRecipientA + `
+    lastmodified: "2024-05-01T10:00:00Z"
+    mac: ENC[AES256_GCM,data:mac=,iv:def=,tag:ghi=,type:str]
+    encrypted_regex: ^password$
+    version: 3.8.1
+`,
+			wantState:  StatePartiallyEncrypted,
+			recipients: []string{testRecipientA},
+		},
+		{
+			name:   "plaintext yaml mentioning mac and lastmodified",
+			format: FormatYAML,
+			content: `mac_address: 00:11:22:33:44:55
+lastmodified_by: alice
+`,
+			wantState: StatePlaintext,
+		},
+		{
+			name:   "plaintext yaml with a sops key that looks like metadata",
+			format: FormatYAML,
+			content: `tools:
+    helm: 3.14.0
+sops:
+    version: 3.8.1
+    lastmodified: "2024-05-01T10:00:00Z"
+`,
+			wantState: StatePlaintext,
+		},
+		{
+			name:      "plaintext json with a sops key holding a MAC but no master key",
+			format:    FormatJSON,
+			content:   `{"sops": {"mac": "not-a-mac", "version": "3.8.1"}, "host": "localhost"}`,
+			wantState: StatePlaintext,
+		},
+		{
+			name:      "plaintext json array",
+			format:    FormatJSON,
+			content:   `[{"host": "localhost"}, {"host": "db.internal"}]`,
+			wantState: StatePlaintext,
+		},
+		{
+			name:      "plaintext json string",
+			format:    FormatJSON,
+			content:   `"just a string"`,
+			wantState: StatePlaintext,
+		},
+		{
+			name:   "yaml encrypted with a KMS key only",
+			format: FormatYAML,
+			content: `password: ENC[AES256_GCM,data:abc=,iv:def=,tag:ghi=,type:str]
+sops:
+    kms:
+        - arn: arn:aws:kms:eu-west-1:111122223333:key/example
+    mac: ENC[AES256_GCM,data:mac=,iv:def=,tag:ghi=,type:str]
+    version: 3.8.1
+`,
+			wantState: StateFullyEncrypted,
+		},
+		{
+			name:   "json",
+			format: FormatJSON,
+			content: `{
+	"password": "ENC[AES256_GCM,data:abc=,iv:def=,tag:ghi=,type:str]",
+	"sops": {
+		"age": [{"recipient": "` + testRecipientA + `", "enc": "..."}],
+		"lastmodified": "2024-05-01T10:00:00Z",
+		"mac": "ENC[AES256_GCM,data:mac=,iv:def=,tag:ghi=,type:str]",
+		"version": "3.8.1"
+	}
+}`,
+			wantState:  StateFullyEncrypted,
+			recipients: []string{testRecipientA},
+		},
+		{
+			name:   "dotenv",
+			format: FormatDotenv,
+			content: `DB_PASSWORD=ENC[AES256_GCM,data:abc=,iv:def=,tag:ghi=,type:str]
+DB_HOST=localhost
+sops_age__list_1__map_recipient=` + testRecipientB + `
+sops_age__list_0__map_recipient=` + testRecipientA + `
+sops_lastmodified=2024-05-01T10:00:00Z
+sops_mac=ENC[AES256_GCM,data:mac=,iv:def=,tag:ghi=,type:str]
+sops_encrypted_regex=PASSWORD
+sops_version=3.8.1
+`,
+			wantState:  StatePartiallyEncrypted,
+			recipients: []string{testRecipientA, testRecipientB},
+		},
+		{
+			name:   "plaintext dotenv with a sops_version line",
+			format: FormatDotenv,
+			content: `DB_HOST=localhost
+sops_version=3.8.1
+sops_lastmodified=2024-05-01T10:00:00Z
+`,
+			wantState: StatePlaintext,
+		},
+		{
+			name:   "dotenv with a MAC but no master key",
+			format: FormatDotenv,
+			content: `DB_PASSWORD=ENC[AES256_GCM,data:abc=,iv:def=,tag:ghi=,type:str]
+sops_mac=ENC[AES256_GCM,data:mac=,iv:def=,tag:ghi=,type:str]
+sops_version=3.8.1
+`,
+			wantState: StatePlaintext,
+		},
+		{
+			name:   "ini",
+			format: FormatINI,
+			content: `[database]
+password = ENC[AES256_GCM,data:abc=,iv:def=,tag:ghi=,type:str]
+
+[sops]
+age__list_0__map_recipient = ` + testRecipientA + `
+lastmodified = 2024-05-01T10:00:00Z
+mac = ENC[AES256_GCM,data:mac=,iv:def=,tag:ghi=,type:str]
+version = 3.8.1
+`,
+			wantState:  StateFullyEncrypted,
+			recipients: []string{testRecipientA},
+		},
+		{
+			name:   "binary",
+			format: FormatBinary,
+			content: `{"data": "ENC[AES256_GCM,data:abc=,iv:def=,tag:ghi=,type:str]", "sops": {
+	"age": [{"recipient": "` + testRecipientA + `"}],
+	"lastmodified": "2024-05-01T10:00:00Z",
+	"mac": "ENC[AES256_GCM,data:mac=,iv:def=,tag:ghi=,type:str]",
+	"version": "3.8.1"
+}}`,
+			wantState:  StateFullyEncrypted,
+			recipients: []string{testRecipientA},
+		},
+		{
+			name:      "plaintext binary",
+			format:    FormatBinary,
+			content:   "not json at all, mac lastmodified sops:",
+			wantState: StatePlaintext,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// When: parsing the content
+			metadata, err := ParseSOPSMetadata([]byte(tc.content), tc.format)
+
+			// Then: the state and recipients are recognised
+			requireNoError(t, err, "parsing should succeed")
+			if metadata.State != tc.wantState {
+				t.Errorf("expected state %s, got %s", tc.wantState, metadata.State)
+			}
+			verifyRecipients(t, metadata.AgeRecipients, tc.recipients)
+		})
+	}
+}
+
+func TestParseSOPSMetadata_Fields(t *testing.T) {
+	t.Parallel()
+
+	// Given: a SOPS YAML file with all commonly used metadata fields
+	content := `secret: ENC[AES256_GCM,data:abc=,iv:def=,tag:ghi=,type:str]
+sops:
+    age:
+        - recipient: ` + testRecipientA + `
+    lastmodified: "2024-05-01T10:00:00Z"
+    mac: ENC[AES256_GCM,data:mac=,iv:def=,tag:ghi=,type:str]
+    encrypted_regex: ^secret$
+    unencrypted_suffix: _unencrypted
+    version: 3.8.1
+`
+
+	// When: parsing it
+	metadata, err := ParseSOPSMetadata([]byte(content), FormatYAML)
+
+	// Then: every field is populated
+	requireNoError(t, err, "parsing should succeed")
+	if !metadata.LastModified.Equal(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected lastmodified: %s", metadata.LastModified)
+	}
+	if metadata.MAC == "" {
+		t.Error("expected MAC to be set")
+	}
+	if metadata.Version != "3.8.1" {
+		t.Errorf("expected version 3.8.1, got %s", metadata.Version)
+	}
+	if metadata.EncryptedRegex != "^secret$" {
+		t.Errorf("expected encrypted_regex ^secret$, got %s", metadata.EncryptedRegex)
+	}
+	if metadata.UnencryptedSuffix != "_unencrypted" {
+		t.Errorf("expected unencrypted_suffix _unencrypted, got %s", metadata.UnencryptedSuffix)
+	}
+}
+
+func TestDetectSOPSFormat(t *testing.T) {
+	t.Parallel()
+
+	expected := map[string]SOPSFormat{
+		"secrets.sops.yaml": FormatYAML,
+		"config.yml":        FormatYAML,
+		"data.json":         FormatJSON,
+		"prod.env":          FormatDotenv,
+		"app.INI":           FormatINI,
+		"secrets/cert":      FormatBinary,
+	}
+
+	for path, want := range expected {
+		if got := DetectSOPSFormat(path); got != want {
+			t.Errorf("DetectSOPSFormat(%s) = %s, want %s", path, got, want)
+		}
+	}
+}
+
+func verifyRecipients(t *testing.T, got, want []string) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Errorf("expected %d recipients, got %d (%v)", len(want), len(got), got)
+		return
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("recipient %d: expected %s, got %s", i, want[i], got[i])
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package core
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPlanner_ExcludesSuspendedMembers(t *testing.T) {
+	t.Parallel()
+
+	// Given: dev.env is encrypted for alice and bob, and bob is suspended
+	tempDir := t.TempDir()
+	devFile := writeTestFile(t, tempDir, "dev.env", sopsDotenvContent(testRecipientA, testRecipientB))
+	manifest := createScopedManifest(tempDir)
+	manifest.Members[1].Suspended = time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	// When: planning
+	plan, err := NewPlanner("sops").ComputePlan(manifest)
+
+	// Then: bob is removed from the recipients, and the suspension is shown
+	requireNoError(t, err, "computing plan should succeed")
+	action := findActionForFile(t, plan, devFile)
+	if !slices.Equal(action.Recipients, []string{testRecipientA}) || !slices.Equal(action.RemovedRecipients, []string{"bob"}) {
+		t.Errorf("expected only alice to remain, got recipients %v, removed %v", action.Recipients, action.RemovedRecipients)
+	}
+	if action.Description != "Member suspended, re-encrypt to remove" {
+		t.Errorf("expected a suspension re-encryption, got %q", action.Description)
+	}
+
+	var output bytes.Buffer
+	plan.Display(NewWriterReporter(&output))
+	if !containsString(output.String(), "Suspended: bob") {
+		t.Errorf("expected suspended members in plan, got: %s", output.String())
+	}
+}
+
+func TestSopsManager_SuspendAndResume(t *testing.T) {
+	t.Parallel()
+
+	// Given: bob reads development
+	service, output, _ := setupScopeTest(t)
+
+	// When: suspending him
+	requireNoError(t, service.SuspendMember("bob"), "suspending should succeed")
+	requireError(t, service.SuspendMember("bob"), "suspending twice should fail")
+
+	// Then: he keeps his key and scopes but is no longer a recipient, and list says so
+	manifest := loadManifestOrFail(t, service.configPath)
+	bob := manifest.FindMember("bob")
+	if !bob.IsSuspended() || bob.AgeKey != testRecipientB || !slices.Contains(manifest.FindScope("development").Members, "bob") {
+		t.Errorf("expected bob to be suspended but kept, got %+v", bob)
+	}
+	members, err := manifest.GetScopeMembers("development")
+	requireNoError(t, err, "resolving members should succeed")
+	if ids := MapSlice(members, func(m Member) string { return m.ID }); !slices.Equal(ids, []string{"alice"}) {
+		t.Errorf("expected only alice as recipient, got %v", ids)
+	}
+	requireNoError(t, service.List(false), "list should succeed")
+	if !containsString(output.String(), "bob: "+shortenKey(testRecipientB)+" (suspended since ") {
+		t.Errorf("expected bob marked as suspended, got: %s", output.String())
+	}
+
+	// When: resuming him
+	requireNoError(t, service.ResumeMember("bob"), "resuming should succeed")
+	requireError(t, service.ResumeMember("bob"), "resuming an active member should fail")
+
+	// Then: he is active again
+	if loadManifestOrFail(t, service.configPath).FindMember("bob").IsSuspended() {
+		t.Error("expected bob to be active after resuming")
+	}
+}
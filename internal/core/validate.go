@@ -0,0 +1,335 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Diagnostic is a single problem found in the manifest, positioned in its YAML source
+type Diagnostic struct {
+	Message string `json:"message"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%d:%d: %s", d.Line, d.Column, d.Message)
+}
+
+// ValidationError lists every problem found in a manifest
+type ValidationError struct {
+	Path        string
+	Diagnostics []Diagnostic
+}
+
+func (e *ValidationError) Error() string {
+	lines := MapSlice(e.Diagnostics, func(d Diagnostic) string { return e.Path + ":" + d.String() })
+	return fmt.Sprintf("%d problem(s) found:\n  %s", len(lines), strings.Join(lines, "\n  "))
+}
+
+// ValidateManifest reads the manifest at path and returns every problem in it.
+// The error is only set when the file cannot be read or is not valid YAML.
+func ValidateManifest(path string) ([]Diagnostic, error) {
+	_, diagnostics, err := parseManifest(path)
+	return diagnostics, err
+}
+
+// parseManifest decodes the manifest at path and validates it against its YAML nodes
+func parseManifest(path string) (*Manifest, []Diagnostic, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // Reading user-provided config file is expected
+	if err != nil {
+		return nil, nil, NewManifestError("load", path, err)
+	}
+	return parseManifestData(path, data)
+}
+
+// parseManifestData decodes manifest data read from path and validates it
+func parseManifestData(path string, data []byte) (*Manifest, []Diagnostic, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, nil, NewManifestError("parse", path, err)
+	}
+
+	var manifest Manifest
+	if root.Kind == 0 { // Empty file
+		return &manifest, nil, nil
+	}
+	if err := root.Decode(&manifest); err != nil {
+		return nil, nil, NewManifestError("parse", path, err)
+	}
+
+	return &manifest, validateManifestNodes(&manifest, root.Content[0]), nil
+}
+
+// manifestValidator collects diagnostics for a decoded manifest and the
+// mapping node it was decoded from
+type manifestValidator struct {
+	diagnostics []Diagnostic
+}
+
+func validateManifestNodes(manifest *Manifest, root *yaml.Node) []Diagnostic {
+	v := &manifestValidator{}
+	v.validateMembers(manifest.Members, nodeOr(mappingValue(root, "members"), root))
+	v.validateGroups(manifest, mappingValue(root, "groups"))
+	v.validateScopes(manifest, nodeOr(mappingValue(root, "scopes"), root))
+	v.validateDefaultScopes(manifest, nodeOr(mappingValue(root, "default_scopes"), root))
+	v.validateSettings(manifest.Settings, nodeOr(mappingValue(root, "settings"), root))
+	return v.diagnostics
+}
+
+func (v *manifestValidator) validateMembers(members []Member, node *yaml.Node) {
+	firstSeen := make(map[string]int, len(members))
+	for i, member := range members {
+		item := nodeOr(sequenceItem(node, i), node)
+		idNode := nodeOr(mappingValue(item, "id"), item)
+
+		if _, err := NewMemberID(member.ID); err != nil {
+			v.addf(idNode, "members[%d].id: %v", i, err)
+		} else if line, seen := firstSeen[member.ID]; seen {
+			v.addf(idNode, "duplicate member ID %q (first defined on line %d)", member.ID, line)
+		} else {
+			firstSeen[member.ID] = idNode.Line
+		}
+
+		if _, err := NewAgePublicKey(member.AgeKey); err != nil {
+			v.addf(nodeOr(mappingValue(item, "age_key"), item), "member %s: %v", member.ID, err)
+		}
+		if _, err := ParseMemberType(string(member.Type)); err != nil {
+			v.addf(nodeOr(mappingValue(item, "type"), item), "member %s: %v", member.ID, err)
+		}
+		v.validateMemberKeys(member, nodeOr(mappingValue(item, "keys"), item))
+		v.validateMemberMetadata(member, item)
+	}
+}
+
+func (v *manifestValidator) validateMemberMetadata(member Member, item *yaml.Node) {
+	if member.Email != "" {
+		if err := validateEmail(member.Email); err != nil {
+			v.addf(nodeOr(mappingValue(item, "email"), item), "member %s: %v", member.ID, err)
+		}
+	}
+
+	gitEmailsNode := nodeOr(mappingValue(item, "git_emails"), item)
+	seen := NewSet[string]()
+	for i, email := range member.GitEmails {
+		node := nodeOr(sequenceItem(gitEmailsNode, i), gitEmailsNode)
+		if err := validateEmail(email); err != nil {
+			v.addf(node, "member %s git_emails: %v", member.ID, err)
+		} else if seen.Contains(strings.ToLower(email)) {
+			v.addf(node, "member %s lists git email %q more than once", member.ID, email)
+		}
+		seen.Add(strings.ToLower(email))
+	}
+
+	labelsNode := nodeOr(mappingValue(item, "labels"), item)
+	for _, key := range slices.Sorted(maps.Keys(member.Labels)) {
+		if err := validateLabel(key, member.Labels[key]); err != nil {
+			v.addf(nodeOr(mappingValue(labelsNode, key), labelsNode), "member %s: %v", member.ID, err)
+		}
+	}
+}
+
+func (v *manifestValidator) validateMemberKeys(member Member, node *yaml.Node) {
+	labels := NewSet[string]()
+	for i, key := range member.Keys {
+		item := nodeOr(sequenceItem(node, i), node)
+		labelNode := nodeOr(mappingValue(item, "label"), item)
+
+		if _, err := NewKeyLabel(key.Label); err != nil {
+			v.addf(labelNode, "member %s keys[%d].label: %v", member.ID, i, err)
+		} else if labels.Contains(key.Label) {
+			v.addf(labelNode, "member %s has more than one key labeled %q", member.ID, key.Label)
+		}
+		labels.Add(key.Label)
+
+		if _, err := NewAgePublicKey(key.AgeKey); err != nil {
+			v.addf(nodeOr(mappingValue(item, "age_key"), item), "member %s key %s: %v", member.ID, key.Label, err)
+		}
+	}
+}
+
+func (v *manifestValidator) validateScopes(manifest *Manifest, node *yaml.Node) {
+	memberIDs := NewSet(MapSlice(manifest.Members, func(m Member) string { return m.ID })...)
+	firstSeen := make(map[string]int, len(manifest.Scopes))
+
+	for i, scope := range manifest.Scopes {
+		item := nodeOr(sequenceItem(node, i), node)
+		nameNode := nodeOr(mappingValue(item, "name"), item)
+
+		if _, err := NewScopeName(scope.Name); err != nil {
+			v.addf(nameNode, "scopes[%d].name: %v", i, err)
+		} else if line, seen := firstSeen[scope.Name]; seen {
+			v.addf(nameNode, "duplicate scope name %q (first defined on line %d)", scope.Name, line)
+		} else {
+			firstSeen[scope.Name] = nameNode.Line
+		}
+
+		v.validatePatterns(scope, nodeOr(mappingValue(item, "patterns"), item))
+		v.validateExcludes(scope, nodeOr(mappingValue(item, "exclude"), item))
+
+		membersNode := nodeOr(mappingValue(item, "members"), item)
+		for j, entry := range scope.Members {
+			v.validateMemberRef(manifest, memberIDs, entry, nodeOr(sequenceItem(membersNode, j), membersNode), "scope "+scope.Name)
+		}
+
+		v.validateInheritance(manifest, &manifest.Scopes[i], nodeOr(mappingValue(item, "inherits_members_from"), item))
+	}
+}
+
+// validateInheritance checks that inherited scopes exist and do not lead back to scope
+func (v *manifestValidator) validateInheritance(manifest *Manifest, scope *Scope, node *yaml.Node) {
+	for i, name := range scope.InheritsMembersFrom {
+		if manifest.FindScope(name) == nil {
+			v.addf(nodeOr(sequenceItem(node, i), node), "scope %s inherits members from unknown scope %q", scope.Name, name)
+		}
+	}
+
+	var cycleErr *scopeCycleError
+	// No grant has expired at the zero time, so every entry is walked
+	err := manifest.walkScopeMembers(scope, time.Time{}, nil, func(string, []string, string) {})
+	if errors.As(err, &cycleErr) && cycleErr.cycle[0] == scope.Name {
+		v.addf(node, "scope %s is part of an %v", scope.Name, cycleErr)
+	}
+}
+
+// validateGroups checks group names, their entries and that nesting has no cycles
+func (v *manifestValidator) validateGroups(manifest *Manifest, node *yaml.Node) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return
+	}
+
+	memberIDs := NewSet(MapSlice(manifest.Members, func(m Member) string { return m.ID })...)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, listNode := node.Content[i], node.Content[i+1]
+		name := keyNode.Value
+		if _, err := NewGroupName(name); err != nil {
+			v.addf(keyNode, "groups.%s: %v", name, err)
+			continue
+		}
+
+		for j, entry := range manifest.Groups[name] {
+			v.validateMemberRef(manifest, memberIDs, entry, nodeOr(sequenceItem(listNode, j), listNode), "group "+name)
+		}
+
+		var cycleErr *groupCycleError
+		if _, err := manifest.ExpandMembers([]string{groupPrefix + name}); errors.As(err, &cycleErr) {
+			v.addf(keyNode, "group %s is part of a %v", name, cycleErr)
+		}
+	}
+}
+
+// validateMemberRef checks that a scope or group entry names a known member or @group
+func (v *manifestValidator) validateMemberRef(manifest *Manifest, memberIDs *Set[string], entry string, node *yaml.Node, owner string) {
+	if group, isGroup := groupRef(entry); isGroup {
+		if _, ok := manifest.Groups[group]; !ok {
+			v.addf(node, "%s references unknown group %q", owner, group)
+		}
+		return
+	}
+	if !memberIDs.Contains(entry) {
+		v.addf(node, "%s references unknown member %q", owner, entry)
+	}
+}
+
+func (v *manifestValidator) validateDefaultScopes(manifest *Manifest, node *yaml.Node) {
+	for i, name := range manifest.DefaultScopes {
+		if manifest.FindScope(name) == nil {
+			v.addf(nodeOr(sequenceItem(node, i), node), "default_scopes references unknown scope %q", name)
+		}
+	}
+}
+
+func (v *manifestValidator) validateSettings(settings Settings, node *yaml.Node) {
+	if _, err := ParseFileDiscovery(settings.FileDiscovery); err != nil {
+		v.addf(nodeOr(mappingValue(node, "file_discovery"), node), "settings.file_discovery: %v", err)
+	}
+	if _, err := ParseScopeOverlap(settings.ScopeOverlap); err != nil {
+		v.addf(nodeOr(mappingValue(node, "scope_overlap"), node), "settings.scope_overlap: %v", err)
+	}
+	if settings.MaxMachineKeyAgeDays < 0 {
+		v.addf(nodeOr(mappingValue(node, "max_machine_key_age_days"), node), "settings.max_machine_key_age_days must not be negative")
+	}
+}
+
+func (v *manifestValidator) validatePatterns(scope Scope, node *yaml.Node) {
+	if len(scope.Patterns) == 0 {
+		v.addf(node, "scope %s has no file patterns", scope.Name)
+		return
+	}
+
+	for i, pattern := range scope.Patterns {
+		patternNode := nodeOr(sequenceItem(node, i), node)
+		if strings.TrimSpace(pattern) == "" {
+			v.addf(patternNode, "scope %s: pattern %d is empty", scope.Name, i+1)
+		} else if _, err := parseFilePattern(pattern); err != nil {
+			v.addf(patternNode, "scope %s: %v", scope.Name, err)
+		}
+	}
+}
+
+func (v *manifestValidator) validateExcludes(scope Scope, node *yaml.Node) {
+	for i, pattern := range scope.Exclude {
+		patternNode := nodeOr(sequenceItem(node, i), node)
+		if strings.TrimSpace(pattern) == "" {
+			v.addf(patternNode, "scope %s: exclude pattern %d is empty", scope.Name, i+1)
+		} else if parsed, err := parseFilePattern(pattern); err != nil {
+			v.addf(patternNode, "scope %s: %v", scope.Name, err)
+		} else if parsed.negated {
+			v.addf(patternNode, "scope %s: exclude pattern %q cannot be negated", scope.Name, pattern)
+		}
+	}
+}
+
+func (v *manifestValidator) addf(node *yaml.Node, format string, args ...any) {
+	v.diagnostics = append(v.diagnostics, Diagnostic{
+		Message: fmt.Sprintf(format, args...),
+		Line:    node.Line,
+		Column:  node.Column,
+	})
+}
+
+// mappingValue returns the value node for key in a mapping node, or nil.
+// Aliases are followed, so "members: *devs" reads as the anchored list.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	node = resolveAlias(node)
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return resolveAlias(node.Content[i+1])
+		}
+	}
+	return nil
+}
+
+// resolveAlias returns the node an alias refers to, or node itself
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node != nil && node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	return node
+}
+
+// sequenceItem returns the i:th item of a sequence node, or nil
+func sequenceItem(node *yaml.Node, i int) *yaml.Node {
+	if node == nil || node.Kind != yaml.SequenceNode || i >= len(node.Content) {
+		return nil
+	}
+	return node.Content[i]
+}
+
+// nodeOr returns node, or fallback when node is missing from the source
+func nodeOr(node, fallback *yaml.Node) *yaml.Node {
+	if node == nil {
+		return fallback
+	}
+	return node
+}
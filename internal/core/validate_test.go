@@ -0,0 +1,78 @@
+package core
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadManifest_ReportsAllProblemsWithPositions(t *testing.T) {
+	t.Parallel()
+
+	// Given: a manifest with several independent problems
+	tempDir := t.TempDir()
+	path := writeTestFile(t, tempDir, "sopsistry.yaml", `members:
+  - id: alice
+    age_key: `+testRecipientA+`
+  - id: alice
+    age_key: age1notakey
+scopes:
+  - name: default
+    patterns: []
+    members:
+      - alice
+      - mallory
+  - name: prod
+    patterns:
+      - ""
+    members: [alice]
+`)
+
+	// When: loading it
+	_, err := LoadManifest(path)
+
+	// Then: every problem is reported at once, each with its position
+	requireError(t, err, "invalid manifest should be refused")
+	if ExitCode(err) != ExitManifest {
+		t.Errorf("expected manifest exit code, got %d", ExitCode(err))
+	}
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected ValidationError, got %T: %v", err, err)
+	}
+
+	want := []Diagnostic{
+		{Line: 4, Column: 9, Message: `duplicate member ID "alice" (first defined on line 2)`},
+		{Line: 5, Column: 14, Message: "member alice: invalid age public key format: must match age1[58 characters]"},
+		{Line: 8, Column: 15, Message: "scope default has no file patterns"},
+		{Line: 11, Column: 9, Message: `scope default references unknown member "mallory"`},
+		{Line: 14, Column: 9, Message: "scope prod: pattern 1 is empty"},
+	}
+	if len(validationErr.Diagnostics) != len(want) {
+		t.Fatalf("expected %d problems, got %d: %v", len(want), len(validationErr.Diagnostics), err)
+	}
+	for i, got := range validationErr.Diagnostics {
+		if got != want[i] {
+			t.Errorf("problem %d: expected %+v, got %+v", i, want[i], got)
+		}
+	}
+}
+
+func TestValidateManifest_ValidManifest(t *testing.T) {
+	t.Parallel()
+
+	// Given: a saved, consistent manifest
+	tempDir := t.TempDir()
+	path := filepath.Join(tempDir, "sopsistry.yaml")
+	requireNoError(t, createScopedManifest(tempDir).Save(path), "saving manifest should succeed")
+
+	// When: validating it
+	diagnostics, err := ValidateManifest(path)
+
+	// Then: no problems are found
+	requireNoError(t, err, "validation should succeed")
+	if len(diagnostics) != 0 {
+		t.Errorf("expected no problems, got %v", diagnostics)
+	}
+}